@@ -16,3 +16,18 @@ type CacheService interface {
 	// Set sets the value associated with the given key in the cache.
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
 }
+
+// Error is returned when the cache service fails.
+type Error struct {
+	Err error
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// StoreDailyPuzzle stores the serialized puzzle of the day in the cache.
+func StoreDailyPuzzle(ctx context.Context, cache CacheService, puzzle *models.DailyPuzzle, expiration time.Duration) error {
+	jsonBytes, err := jsonMarshal(puzzle)
+	if err != nil {
+		return errors.Wrap(err, "serializing daily puzzle")
+	}
+	return cache.Set(ctx, dailyPuzzleCacheKey(puzzle.Date), string(jsonBytes), expiration)
+}
+
+// RetrieveDailyPuzzle retrieves the puzzle of the given date from the cache.
+// It returns nil when the puzzle is not cached.
+func RetrieveDailyPuzzle(ctx context.Context, cache CacheService, date string) (*models.DailyPuzzle, error) {
+	serializedPuzzle, err := cache.Get(ctx, dailyPuzzleCacheKey(date))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve daily puzzle")
+	}
+	if serializedPuzzle == "" {
+		return nil, nil
+	}
+	var puzzle models.DailyPuzzle
+	if err := jsonUnmarshal([]byte(serializedPuzzle), &puzzle); err != nil {
+		return nil, errors.Wrap(err, "deserializing daily puzzle")
+	}
+	return &puzzle, nil
+}
+
+// dailyPuzzleCacheKey generates a cache key for the puzzle of the given date.
+func dailyPuzzleCacheKey(date string) string {
+	return "daily#" + date
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// StoreJob stores the serialized job in the cache.
+func StoreJob(ctx context.Context, cache CacheService, job *models.Job, expiration time.Duration) error {
+	jsonBytes, err := jsonMarshal(job)
+	if err != nil {
+		return errors.Wrap(err, "serializing job")
+	}
+	return cache.Set(ctx, jobCacheKey(job.ID), string(jsonBytes), expiration)
+}
+
+// RetrieveJob retrieves the job with the given ID from the cache.
+// It returns nil when the job is not cached.
+func RetrieveJob(ctx context.Context, cache CacheService, id string) (*models.Job, error) {
+	serializedJob, err := cache.Get(ctx, jobCacheKey(id))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve job")
+	}
+	if serializedJob == "" {
+		return nil, nil
+	}
+	var job models.Job
+	if err := jsonUnmarshal([]byte(serializedJob), &job); err != nil {
+		return nil, errors.Wrap(err, "deserializing job")
+	}
+	return &job, nil
+}
+
+// StoreJobCancellation records in the cache that the job with the given ID
+// was canceled, apart from the job itself, so that it can't be overwritten
+// by updates of the job.
+func StoreJobCancellation(ctx context.Context, cache CacheService, id string, expiration time.Duration) error {
+	return cache.Set(ctx, jobCancellationCacheKey(id), "canceled", expiration)
+}
+
+// IsJobCanceled reports whether the cancellation of the job with the given
+// ID is recorded in the cache.
+func IsJobCanceled(ctx context.Context, cache CacheService, id string) (bool, error) {
+	cancellation, err := cache.Get(ctx, jobCancellationCacheKey(id))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to retrieve job cancellation")
+	}
+	return cancellation != "", nil
+}
+
+// jobCacheKey generates a cache key for the job with the given ID.
+func jobCacheKey(id string) string {
+	return "job#" + id
+}
+
+// jobCancellationCacheKey generates a cache key for the cancellation of
+// the job with the given ID.
+func jobCancellationCacheKey(id string) string {
+	return "job-cancellation#" + id
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+func TestStoreJob(t *testing.T) {
+	testCases := []struct {
+		name            string
+		mockJsonMarshal func(v any) ([]byte, error)
+		mockClosure     func(m *mockRedisCache)
+		expectedError   error
+	}{
+		{
+			name: "happy path",
+			mockJsonMarshal: func(v any) ([]byte, error) {
+				return []byte("some value"), nil
+			},
+			mockClosure: func(m *mockRedisCache) {},
+		},
+		{
+			name: "error when serializing",
+			mockJsonMarshal: func(v any) ([]byte, error) {
+				return nil, errors.New("marshal error")
+			},
+			mockClosure:   func(m *mockRedisCache) {},
+			expectedError: errors.New("serializing job: marshal error"),
+		},
+		{
+			name: "error when setting value in Redis",
+			mockJsonMarshal: func(v any) ([]byte, error) {
+				return []byte("some value"), nil
+			},
+			mockClosure: func(m *mockRedisCache) {
+				m.setErr = errors.New("set error")
+			},
+			expectedError: errors.New("set error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			jsonMarshal = tc.mockJsonMarshal
+			m := new(mockRedisCache)
+			tc.mockClosure(m)
+			err := StoreJob(context.TODO(), m, &models.Job{ID: "f3a1c2"}, 24*time.Hour)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error to occur, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error to occur, got nil`)
+				}
+			}
+		})
+	}
+}
+
+func TestRetrieveJob(t *testing.T) {
+	testCases := []struct {
+		name              string
+		mockClosure       func(m *mockRedisCache)
+		mockJsonUnmarshal func(data []byte, v any) error
+		expectedNil       bool
+		expectedError     error
+	}{
+		{
+			name: "happy path",
+			mockClosure: func(m *mockRedisCache) {
+				m.val = "some cached val"
+			},
+			mockJsonUnmarshal: func(data []byte, v any) error {
+				return nil
+			},
+		},
+		{
+			name:        "not cached",
+			mockClosure: func(m *mockRedisCache) {},
+			expectedNil: true,
+		},
+		{
+			name: "error when getting value from Redis",
+			mockClosure: func(m *mockRedisCache) {
+				m.getErr = errors.New("get error")
+			},
+			expectedError: errors.New("failed to retrieve job: get error"),
+		},
+		{
+			name: "error when desserializing",
+			mockClosure: func(m *mockRedisCache) {
+				m.val = "some cached val"
+			},
+			mockJsonUnmarshal: func(data []byte, v any) error {
+				return errors.New("unmarshal error")
+			},
+			expectedError: errors.New("deserializing job: unmarshal error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			jsonUnmarshal = tc.mockJsonUnmarshal
+			m := new(mockRedisCache)
+			tc.mockClosure(m)
+			output, err := RetrieveJob(context.TODO(), m, "f3a1c2")
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error to occur, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error to occur, got nil`)
+				}
+				require.Equal(t, tc.expectedNil, output == nil)
+			}
+		})
+	}
+}
+
+func TestStoreJobCancellation(t *testing.T) {
+	testCases := []struct {
+		name          string
+		mockClosure   func(m *mockRedisCache)
+		expectedError error
+	}{
+		{
+			name:        "happy path",
+			mockClosure: func(m *mockRedisCache) {},
+		},
+		{
+			name: "error when setting value in Redis",
+			mockClosure: func(m *mockRedisCache) {
+				m.setErr = errors.New("set error")
+			},
+			expectedError: errors.New("set error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(mockRedisCache)
+			tc.mockClosure(m)
+			err := StoreJobCancellation(context.TODO(), m, "f3a1c2", 24*time.Hour)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error to occur, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error to occur, got nil`)
+				}
+			}
+		})
+	}
+}
+
+func TestIsJobCanceled(t *testing.T) {
+	testCases := []struct {
+		name           string
+		mockClosure    func(m *mockRedisCache)
+		expectedOutput bool
+		expectedError  error
+	}{
+		{
+			name: "canceled",
+			mockClosure: func(m *mockRedisCache) {
+				m.val = "canceled"
+			},
+			expectedOutput: true,
+		},
+		{
+			name:        "not canceled",
+			mockClosure: func(m *mockRedisCache) {},
+		},
+		{
+			name: "error when getting value from Redis",
+			mockClosure: func(m *mockRedisCache) {
+				m.getErr = errors.New("get error")
+			},
+			expectedError: errors.New("failed to retrieve job cancellation: get error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(mockRedisCache)
+			tc.mockClosure(m)
+			output, err := IsJobCanceled(context.TODO(), m, "f3a1c2")
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error to occur, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error to occur, got nil`)
+				}
+				require.Equal(t, tc.expectedOutput, output)
+			}
+		})
+	}
+}
@@ -89,7 +89,7 @@ func (rc *redisCache) Get(ctx context.Context, key string) (string, error) {
 		if err == redis.Nil {
 			return "", nil
 		}
-		return "", errors.Wrapf(err, `getting key "%s"`, key)
+		return "", &Error{errors.Wrapf(err, `getting key "%s"`, key)}
 	}
 	return getCmd.Val(), nil
 }
@@ -97,7 +97,7 @@ func (rc *redisCache) Get(ctx context.Context, key string) (string, error) {
 func (rc *redisCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
 	statusCmd := rc.redisClient.Set(ctx, key, value, expiration)
 	if err := statusCmd.Err(); err != nil {
-		return errors.Wrapf(err, `set key "%v" and expiration %v`, key, expiration)
+		return &Error{errors.Wrapf(err, `set key "%v" and expiration %v`, key, expiration)}
 	}
 	return nil
 }
@@ -104,6 +104,8 @@ func TestGet(t *testing.T) {
 					t.Fatalf(`expected no error to occur, got "%v"`, err)
 				}
 				require.Equal(t, tc.expectedError.Error(), err.Error())
+				var cacheErr *Error
+				require.ErrorAs(t, err, &cacheErr)
 			} else {
 				if tc.expectedError != nil {
 					t.Fatalf(`expected error to occur, got nil`)
@@ -147,6 +149,8 @@ func TestSet(t *testing.T) {
 					t.Fatalf(`expected no error to occur, got "%v"`, err)
 				}
 				require.Equal(t, tc.expectedError.Error(), err.Error())
+				var cacheErr *Error
+				require.ErrorAs(t, err, &cacheErr)
 			} else {
 				if tc.expectedError != nil {
 					t.Fatalf(`expected error to occur, got nil`)
@@ -8,6 +8,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -62,5 +65,47 @@ func RetrieveSolution(ctx context.Context, cache CacheService, measurement *mode
 
 // solutionCacheKey generates a cache key based on the measurement parameters.
 func solutionCacheKey(measurement *models.NewMeasurement) string {
-	return fmt.Sprintf("%d#%d#%d", measurement.XCap, measurement.YCap, measurement.ZAmountWanted)
+	key := fmt.Sprintf("%d#%d#%d#%s#%s", measurement.XCap, measurement.YCap, measurement.ZAmountWanted,
+		measurement.Strategy, goalCacheKey(measurement.Goal))
+	if measurement.Accumulate {
+		key += "#accumulate"
+	}
+	if measurement.MaxSteps > 0 {
+		key += fmt.Sprintf("#max=%d", measurement.MaxSteps)
+	}
+	if len(measurement.ActionOrder) > 0 {
+		key += "#order=" + strings.Join(measurement.ActionOrder, "|")
+	}
+	return key
+}
+
+// goalCacheKey generates a canonical representation of the goal specification,
+// so that equivalent goals share the same cache key.
+func goalCacheKey(goal *models.Goal) string {
+	if goal == nil {
+		return ""
+	}
+	var parts []string
+	if goal.X != nil {
+		parts = append(parts, fmt.Sprintf("x=%d", *goal.X))
+	}
+	if goal.Y != nil {
+		parts = append(parts, fmt.Sprintf("y=%d", *goal.Y))
+	}
+	if len(goal.AnyOf) > 0 {
+		anyOf := append([]int(nil), goal.AnyOf...)
+		sort.Ints(anyOf)
+		amounts := make([]string, 0, len(anyOf))
+		for i, amount := range anyOf {
+			if i > 0 && amount == anyOf[i-1] {
+				continue
+			}
+			amounts = append(amounts, strconv.Itoa(amount))
+		}
+		parts = append(parts, "any="+strings.Join(amounts, "|"))
+	}
+	if goal.Difference != nil {
+		parts = append(parts, fmt.Sprintf("diff=%d", *goal.Difference))
+	}
+	return strings.Join(parts, ",")
 }
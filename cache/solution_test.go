@@ -125,3 +125,47 @@ func TestRetrieveSolution(t *testing.T) {
 		})
 	}
 }
+
+func TestSolutionCacheKey(t *testing.T) {
+	two, one := 2, 1
+	testCases := []struct {
+		name           string
+		measurement    *models.NewMeasurement
+		expectedOutput string
+	}{
+		{
+			name:           "default goal",
+			measurement:    &models.NewMeasurement{XCap: 2, YCap: 100, ZAmountWanted: 96, Strategy: "bfs"},
+			expectedOutput: "2#100#96#bfs#",
+		},
+		{
+			name: "custom goal",
+			measurement: &models.NewMeasurement{XCap: 5, YCap: 3, Strategy: "bfs", Goal: &models.Goal{
+				X:          &two,
+				AnyOf:      []int{7, 2, 7},
+				Difference: &one,
+			}},
+			expectedOutput: "5#3#0#bfs#x=2,any=2|7,diff=1",
+		},
+		{
+			name:           "accumulation",
+			measurement:    &models.NewMeasurement{XCap: 3, YCap: 5, ZAmountWanted: 13, Strategy: "bfs", Accumulate: true},
+			expectedOutput: "3#5#13#bfs##accumulate",
+		},
+		{
+			name:           "maximum number of steps",
+			measurement:    &models.NewMeasurement{XCap: 3, YCap: 5, ZAmountWanted: 4, Strategy: "bfs", MaxSteps: 5},
+			expectedOutput: "3#5#4#bfs##max=5",
+		},
+		{
+			name:           "action order",
+			measurement:    &models.NewMeasurement{XCap: 3, YCap: 5, ZAmountWanted: 4, Strategy: "bfs", ActionOrder: []string{"Fill bucket X", "Empty bucket Y"}},
+			expectedOutput: "3#5#4#bfs##order=Fill bucket X|Empty bucket Y",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedOutput, solutionCacheKey(tc.measurement))
+		})
+	}
+}
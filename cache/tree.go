@@ -0,0 +1,45 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/golang-waterjug-api/measurement"
+)
+
+// StoreTree stores the shortest-path tree of a jug pair in the cache, in its compact binary form.
+func StoreTree(ctx context.Context, cache CacheService, xCap, yCap int, tree *measurement.Tree, expiration time.Duration) error {
+	data, err := tree.MarshalBinary()
+	if err != nil {
+		return errors.Wrap(err, "serializing tree")
+	}
+	return cache.Set(ctx, treeCacheKey(xCap, yCap), data, expiration)
+}
+
+// RetrieveTree retrieves the shortest-path tree of a jug pair from the cache.
+// It returns nil when the tree is not cached.
+func RetrieveTree(ctx context.Context, cache CacheService, xCap, yCap int) (*measurement.Tree, error) {
+	data, err := cache.Get(ctx, treeCacheKey(xCap, yCap))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve tree")
+	}
+	if data == "" {
+		return nil, nil
+	}
+	tree := new(measurement.Tree)
+	if err := tree.UnmarshalBinary([]byte(data)); err != nil {
+		return nil, errors.Wrap(err, "deserializing tree")
+	}
+	return tree, nil
+}
+
+// treeCacheKey generates a cache key for the tree of a jug pair.
+func treeCacheKey(xCap, yCap int) string {
+	return fmt.Sprintf("tree#%d#%d", xCap, yCap)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/measurement"
+)
+
+func TestStoreTree(t *testing.T) {
+	testCases := []struct {
+		name          string
+		mockClosure   func(m *mockRedisCache)
+		expectedError error
+	}{
+		{
+			name:        "happy path",
+			mockClosure: func(m *mockRedisCache) {},
+		},
+		{
+			name: "error when setting value in Redis",
+			mockClosure: func(m *mockRedisCache) {
+				m.setErr = errors.New("set error")
+			},
+			expectedError: errors.New("set error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(mockRedisCache)
+			tc.mockClosure(m)
+			err := StoreTree(context.TODO(), m, 3, 5, measurement.BuildTree(3, 5), 24*time.Hour)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error to occur, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error to occur, got nil`)
+				}
+			}
+		})
+	}
+}
+
+func TestRetrieveTree(t *testing.T) {
+	data, err := measurement.BuildTree(3, 5).MarshalBinary()
+	require.NoError(t, err)
+	testCases := []struct {
+		name          string
+		mockClosure   func(m *mockRedisCache)
+		expectedNil   bool
+		expectedError error
+	}{
+		{
+			name: "happy path",
+			mockClosure: func(m *mockRedisCache) {
+				m.val = string(data)
+			},
+		},
+		{
+			name:        "not cached",
+			mockClosure: func(m *mockRedisCache) {},
+			expectedNil: true,
+		},
+		{
+			name: "error when getting value from Redis",
+			mockClosure: func(m *mockRedisCache) {
+				m.getErr = errors.New("get error")
+			},
+			expectedError: errors.New("failed to retrieve tree: get error"),
+		},
+		{
+			name: "error when desserializing",
+			mockClosure: func(m *mockRedisCache) {
+				m.val = "some cached val"
+			},
+			expectedError: errors.New("deserializing tree: corrupt tree: unsupported version"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(mockRedisCache)
+			tc.mockClosure(m)
+			output, err := RetrieveTree(context.TODO(), m, 3, 5)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error to occur, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error to occur, got nil`)
+				}
+				require.Equal(t, tc.expectedNil, output == nil)
+			}
+		})
+	}
+}
@@ -19,6 +19,8 @@ import (
 	"github.com/tiagomelo/golang-waterjug-api/cache"
 	"github.com/tiagomelo/golang-waterjug-api/config"
 	"github.com/tiagomelo/golang-waterjug-api/handlers"
+	"github.com/tiagomelo/golang-waterjug-api/jobs"
+	"github.com/tiagomelo/golang-waterjug-api/measurement"
 )
 
 type options struct {
@@ -37,6 +39,10 @@ func run(port int, log *slog.Logger) error {
 		return errors.Wrap(err, "reading configuration")
 	}
 
+	if _, err := measurement.Lookup(cfg.SolverStrategy); err != nil {
+		return errors.Wrap(err, "validating solver strategy")
+	}
+
 	// =========================================================================
 	// Redis cache support
 
@@ -45,12 +51,20 @@ func run(port int, log *slog.Logger) error {
 		return errors.Wrap(err, "connecting to Redis")
 	}
 
+	// =========================================================================
+	// Job workers
+
+	jobPool := jobs.NewPool(redisCache, log, cfg.JobWorkers)
+	defer jobPool.Stop()
+
 	// =========================================================================
 	// API Service
 
 	apiMux := handlers.NewApiMux(&handlers.ApiMuxConfig{
-		Cache: redisCache,
-		Log:   log,
+		Cache:    redisCache,
+		Log:      log,
+		Strategy: cfg.SolverStrategy,
+		Jobs:     jobPool,
 	})
 
 	// Server to service the requests against the mux.
@@ -101,7 +115,7 @@ func main() {
 	}
 	log := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 	if err := run(opts.Port, log); err != nil {
-		log.Error("error:", err)
+		log.Error("error", slog.Any("err", err))
 		os.Exit(1)
 	}
 }
@@ -12,8 +12,10 @@ import (
 
 // Config holds all configuration needed by this app.
 type Config struct {
-	RedisHost string `envconfig:"REDIS_HOST" required:"true"`
-	RedisPort string `envconfig:"REDIS_PORT" required:"true"`
+	RedisHost      string `envconfig:"REDIS_HOST" required:"true"`
+	RedisPort      string `envconfig:"REDIS_PORT" required:"true"`
+	SolverStrategy string `envconfig:"SOLVER_STRATEGY" default:"bfs"`
+	JobWorkers     int    `envconfig:"JOB_WORKERS" default:"4"`
 }
 
 // For ease of unit testing.
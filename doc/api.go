@@ -4,18 +4,402 @@
 
 package doc
 
-import "github.com/tiagomelo/golang-waterjug-api/measurement/models"
+import (
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+	"github.com/tiagomelo/golang-waterjug-api/web"
+)
 
 // swagger:route POST /v1/measure measure Get
 // Get measurement.
 // ---
+// produces:
+// - application/json
+// - image/svg+xml
+// - image/png
+// - text/plain
 // responses:
 //		200: getMeasurementResponse
-//		400: description: missing required fields
-//		500: description: internal server error
+//		400: problemResponse
+//		422: noSolutionResponse
+//		500: problemResponse
+//		503: problemResponse
+
+// swagger:parameters Get
+type GetMeasurementParams struct {
+	// in:query
+	Format string `json:"format"`
+	// in:query
+	Debug string `json:"debug"`
+	// in:body
+	Body models.NewMeasurement
+}
 
 // swagger:response getMeasurementResponse
 type GetMeasurementResponseWrapper struct {
+	// in:body
+	Body models.Solution
+}
+
+// NoSolutionProblem is the problem reported when a measurement has no solution.
+type NoSolutionProblem struct {
+	web.Problem
+	models.NoSolution
+}
+
+// Measurement without solution.
+// swagger:response noSolutionResponse
+type NoSolutionResponseWrapper struct {
+	// in:body
+	Body NoSolutionProblem
+}
+
+// Problem describing the error, as application/problem+json (RFC 7807).
+// swagger:response problemResponse
+type ProblemResponseWrapper struct {
+	// in:body
+	Body web.Problem
+}
+
+// swagger:route GET /v1/measure measure GetCacheable
+// Get measurement given as query parameters, with caching headers.
+// ---
+// produces:
+// - application/json
+// - image/svg+xml
+// - image/png
+// - text/plain
+// responses:
+//		200: getCacheableMeasurementResponse
+//		304: description: the solution identified by If-None-Match is still current
+//		400: problemResponse
+//		422: noSolutionResponse
+//		500: problemResponse
+//		503: problemResponse
+
+// swagger:parameters GetCacheable
+type GetCacheableMeasurementParams struct {
+	// in:query
+	X int `json:"x"`
+	// in:query
+	Y int `json:"y"`
+	// in:query
+	Z int `json:"z"`
+	// in:query
+	Format string `json:"format"`
+	// in:header
+	IfNoneMatch string `json:"If-None-Match"`
+}
+
+// swagger:response getCacheableMeasurementResponse
+type GetCacheableMeasurementResponseWrapper struct {
+	// in:header
+	ETag string `json:"ETag"`
+	// in:header
+	CacheControl string `json:"Cache-Control"`
+	// in:body
+	Body models.Solution
+}
+
+// swagger:route POST /v1/measure/batch measure Batch
+// Get measurements of a batch, each with either its solution or the problem that prevented it.
+// ---
+// responses:
+//		200: batchMeasurementResponse
+//		400: problemResponse
+
+// swagger:parameters Batch
+type BatchMeasurementParams struct {
+	// in:body
+	Body []models.NewMeasurement
+}
+
+// BatchMeasurementResult is the result of one measurement of a batch.
+type BatchMeasurementResult struct {
+	Index    int              `json:"index"`
+	Solution *models.Solution `json:"solution,omitempty"`
+	Error    *web.Problem     `json:"error,omitempty"`
+}
+
+// swagger:response batchMeasurementResponse
+type BatchMeasurementResponseWrapper struct {
+	// in:body
+	Body []BatchMeasurementResult
+}
+
+// swagger:route POST /v1/measure/stream measure Stream
+// Get measurements given as JSON Lines, writing a result line per measurement as soon as it's solved.
+// ---
+// consumes:
+// - application/x-ndjson
+// produces:
+// - application/x-ndjson
+// responses:
+//		200: streamMeasurementResponse
+
+// swagger:parameters Stream
+type StreamMeasurementParams struct {
+	// One measurement per line.
 	// in:body
 	Body models.NewMeasurement
 }
+
+// swagger:response streamMeasurementResponse
+type StreamMeasurementResponseWrapper struct {
+	// One result per line.
+	// in:body
+	Body BatchMeasurementResult
+}
+
+// swagger:route GET /v1/measure/stream measure Events
+// Get the solution to a measurement given as query parameters as Server-Sent Events, one per step.
+// ---
+// produces:
+// - text/event-stream
+// responses:
+//		200: eventsMeasurementResponse
+//		400: problemResponse
+
+// swagger:parameters Events
+type EventsMeasurementParams struct {
+	// in:query
+	X int `json:"x"`
+	// in:query
+	Y int `json:"y"`
+	// in:query
+	Z int `json:"z"`
+}
+
+// swagger:response eventsMeasurementResponse
+type EventsMeasurementResponseWrapper struct {
+	// "step" events, each carrying a step, followed by a "solved" event or a
+	// "no-solution" event carrying the problem.
+	// in:body
+	Body models.Step
+}
+
+// swagger:route GET /v1/jugs/{x}/{y}/graph jugs Graph
+// Export the state graph of a jug pair as JSON, Graphviz DOT or GraphML.
+// ---
+// produces:
+// - application/json
+// - text/vnd.graphviz
+// - application/graphml+xml
+// responses:
+//		200: getStateGraphResponse
+//		400: problemResponse
+//		500: problemResponse
+
+// swagger:parameters Graph
+type GetStateGraphParams struct {
+	// in:path
+	X int `json:"x"`
+	// in:path
+	Y int `json:"y"`
+	// in:query
+	Format string `json:"format"`
+	// in:query
+	Target int `json:"target"`
+}
+
+// swagger:response getStateGraphResponse
+type GetStateGraphResponseWrapper struct {
+	// in:body
+	Body models.Graph
+}
+
+// swagger:route GET /v1/jugs/{x}/{y}/steps jugs Steps
+// Get the minimal number of steps for every target of a jug pair, as JSON or CSV.
+// ---
+// produces:
+// - application/json
+// - text/csv
+// responses:
+//		200: getStepsTableResponse
+//		400: problemResponse
+
+// swagger:parameters Steps
+type GetStepsTableParams struct {
+	// in:path
+	X int `json:"x"`
+	// in:path
+	Y int `json:"y"`
+	// in:query
+	Format string `json:"format"`
+}
+
+// swagger:response getStepsTableResponse
+type GetStepsTableResponseWrapper struct {
+	// in:body
+	Body models.StepsTable
+}
+
+// swagger:route GET /v1/jugs/{x}/{y}/analytics jugs Analytics
+// Get analytics over the state graph of a jug pair.
+// ---
+// responses:
+//		200: getStateAnalyticsResponse
+//		400: problemResponse
+//		500: problemResponse
+
+// swagger:parameters Analytics
+type GetStateAnalyticsParams struct {
+	// in:path
+	X int `json:"x"`
+	// in:path
+	Y int `json:"y"`
+}
+
+// swagger:response getStateAnalyticsResponse
+type GetStateAnalyticsResponseWrapper struct {
+	// in:body
+	Body models.StateAnalytics
+}
+
+// swagger:route POST /v1/puzzles/generate puzzles Generate
+// Generate puzzles whose optimal solution has an exact number of steps.
+// Every puzzle is unique, as its optimal solution is the only one of that
+// length, and non-trivial, as its capacities are different, neither is a
+// multiple of the other and the target takes at least two steps.
+// ---
+// responses:
+//		200: generatePuzzlesResponse
+//		400: problemResponse
+//		500: problemResponse
+
+// swagger:parameters Generate
+type GeneratePuzzlesParams struct {
+	// in:body
+	Body models.NewPuzzles
+}
+
+// swagger:response generatePuzzlesResponse
+type GeneratePuzzlesResponseWrapper struct {
+	// in:body
+	Body models.GeneratedPuzzles
+}
+
+// swagger:route GET /v1/puzzles/daily puzzles Daily
+// Get the puzzle of the day.
+// ---
+// responses:
+//		200: dailyPuzzleResponse
+//		400: problemResponse
+//		500: problemResponse
+//		503: problemResponse
+
+// swagger:parameters Daily
+type DailyPuzzleParams struct {
+	// in:query
+	Date string `json:"date"`
+}
+
+// swagger:response dailyPuzzleResponse
+type DailyPuzzleResponseWrapper struct {
+	// in:body
+	Body models.DailyPuzzle
+}
+
+// swagger:route POST /v1/puzzles/daily/verify puzzles Verify
+// Verify a procedure for the puzzle of the day, revealing its solution once solved.
+// ---
+// responses:
+//		200: dailyVerificationResponse
+//		400: problemResponse
+//		500: problemResponse
+//		503: problemResponse
+
+// swagger:parameters Verify
+type VerifyDailyParams struct {
+	// in:query
+	Date string `json:"date"`
+	// in:body
+	Body models.DailyAttempt
+}
+
+// swagger:response dailyVerificationResponse
+type DailyVerificationResponseWrapper struct {
+	// in:body
+	Body models.DailyVerification
+}
+
+// swagger:route GET /v1/play play Play
+// Play a puzzle over a WebSocket connection: send models.PlayMessage messages, receive models.PlayEvent events.
+// ---
+// responses:
+//		101: description: switching to the WebSocket protocol
+//		400: problemResponse
+//		422: noSolutionResponse
+
+// swagger:parameters Play
+type PlayParams struct {
+	// in:query
+	X int `json:"x"`
+	// in:query
+	Y int `json:"y"`
+	// in:query
+	Z int `json:"z"`
+}
+
+// swagger:route POST /v1/recommend recommend Recommend
+// Rank the pairs of jugs from an inventory able to measure an amount.
+// ---
+// responses:
+//		200: recommendationResponse
+//		400: problemResponse
+//		422: noSolutionResponse
+
+// swagger:parameters Recommend
+type RecommendParams struct {
+	// in:body
+	Body models.NewRecommendation
+}
+
+// swagger:response recommendationResponse
+type RecommendationResponseWrapper struct {
+	// in:body
+	Body models.Recommendation
+}
+
+// swagger:route POST /v1/jobs jobs CreateJob
+// Submit a measurement to be solved asynchronously.
+// ---
+// responses:
+//		202: jobResponse
+//		400: problemResponse
+//		500: problemResponse
+//		503: problemResponse
+
+// swagger:parameters CreateJob
+type CreateJobParams struct {
+	// in:body
+	Body models.NewJob
+}
+
+// swagger:route GET /v1/jobs/{id} jobs GetJob
+// Poll a job.
+// ---
+// responses:
+//		200: jobResponse
+//		404: problemResponse
+//		503: problemResponse
+
+// swagger:route DELETE /v1/jobs/{id} jobs CancelJob
+// Cancel a job that hasn't finished.
+// ---
+// responses:
+//		200: jobResponse
+//		404: problemResponse
+//		409: problemResponse
+//		503: problemResponse
+
+// swagger:parameters GetJob CancelJob
+type JobParams struct {
+	// in:path
+	ID string `json:"id"`
+}
+
+// swagger:response jobResponse
+type JobResponseWrapper struct {
+	// in:body
+	Body models.Job
+}
@@ -10,18 +10,23 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/tiagomelo/golang-waterjug-api/cache"
 	v1 "github.com/tiagomelo/golang-waterjug-api/handlers/v1"
+	"github.com/tiagomelo/golang-waterjug-api/jobs"
 )
 
 // ApiMuxConfig struct holds the configuration for the API.
 type ApiMuxConfig struct {
-	Cache cache.CacheService
-	Log   *slog.Logger
+	Cache    cache.CacheService
+	Log      *slog.Logger
+	Strategy string
+	Jobs     *jobs.Pool
 }
 
 // NewApiMux creates and returns a new mux.Router configured with version 1 (v1) routes.
 func NewApiMux(c *ApiMuxConfig) *mux.Router {
 	return v1.Routes(&v1.Config{
-		Cache: c.Cache,
-		Log:   c.Log,
+		Cache:    c.Cache,
+		Log:      c.Log,
+		Strategy: c.Strategy,
+		Jobs:     c.Jobs,
 	})
 }
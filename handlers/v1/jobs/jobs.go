@@ -0,0 +1,139 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+
+	"github.com/gorilla/mux"
+	"github.com/tiagomelo/golang-waterjug-api/jobs"
+	"github.com/tiagomelo/golang-waterjug-api/measurement"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+	"github.com/tiagomelo/golang-waterjug-api/validate"
+	"github.com/tiagomelo/golang-waterjug-api/web"
+)
+
+// pool runs jobs and keeps track of them.
+type pool interface {
+	// Submit queues the given job.
+	Submit(ctx context.Context, job *models.Job) error
+	// Get returns the job with the given ID.
+	Get(ctx context.Context, id string) (*models.Job, error)
+	// Cancel cancels the job with the given ID.
+	Cancel(ctx context.Context, id string) (*models.Job, error)
+}
+
+// handlers represents HTTP handlers for asynchronous measurements.
+type handlers struct {
+	pool     pool
+	strategy string
+}
+
+// For ease of unit testing.
+var (
+	// jsonDecode decodes a JSON request body into a given struct.
+	jsonDecode = func(r io.Reader, v any) error {
+		return json.NewDecoder(r).Decode(v)
+	}
+	// newJobID generates a random job ID.
+	newJobID = func() (string, error) {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(b), nil
+	}
+)
+
+// New creates a new handlers instance with the pool running the jobs
+// and the solving strategy used when a request does not specify one.
+func New(pool pool, strategy string) *handlers {
+	return &handlers{
+		pool:     pool,
+		strategy: strategy,
+	}
+}
+
+// jobError marks the errors of the pool with the status code to respond with.
+func jobError(err error) error {
+	switch {
+	case errors.Is(err, jobs.ErrQueueFull), errors.Is(err, jobs.ErrStopped):
+		return web.WithStatus(http.StatusServiceUnavailable, err)
+	case errors.Is(err, jobs.ErrNotFound):
+		return web.WithStatus(http.StatusNotFound, err)
+	case errors.Is(err, jobs.ErrFinished):
+		return web.WithStatus(http.StatusConflict, err)
+	}
+	return err
+}
+
+// Create is an HTTP handler for submitting a measurement to be solved
+// asynchronously, so that large problems don't hit proxy timeouts. The
+// measurement is validated right away. The response is the queued job,
+// whose status URL, also given as the Location header, is to be polled.
+// With a callback URL and secret, the finished job is also posted there.
+func (h *handlers) Create(w http.ResponseWriter, r *http.Request) error {
+	defer r.Body.Close()
+	var newJob models.NewJob
+	if err := jsonDecode(r.Body, &newJob); err != nil {
+		return web.BadRequest(err)
+	}
+	if err := validate.Check(newJob); err != nil {
+		return err
+	}
+	newMeasurement := &newJob.NewMeasurement
+	if _, err := measurement.NewProblem(newMeasurement); err != nil {
+		return err
+	}
+	if newMeasurement.Strategy == "" {
+		newMeasurement.Strategy = h.strategy
+	}
+	id, err := newJobID()
+	if err != nil {
+		return err
+	}
+	job := &models.Job{
+		ID:             id,
+		StatusURL:      path.Join(r.URL.Path, id),
+		Measurement:    newMeasurement,
+		CallbackURL:    newJob.CallbackURL,
+		CallbackSecret: newJob.CallbackSecret,
+	}
+	if err := h.pool.Submit(r.Context(), job); err != nil {
+		return jobError(err)
+	}
+	w.Header().Set("Location", job.StatusURL)
+	web.RespondWithJson(w, http.StatusAccepted, job)
+	return nil
+}
+
+// Get is an HTTP handler for polling a job: its status, the progress of
+// its search while running and, once finished, its solution or the problem
+// that prevented it from being solved.
+func (h *handlers) Get(w http.ResponseWriter, r *http.Request) error {
+	job, err := h.pool.Get(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		return jobError(err)
+	}
+	web.RespondWithJson(w, http.StatusOK, job)
+	return nil
+}
+
+// Delete is an HTTP handler for canceling a job that hasn't finished.
+func (h *handlers) Delete(w http.ResponseWriter, r *http.Request) error {
+	job, err := h.pool.Cancel(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		return jobError(err)
+	}
+	web.RespondWithJson(w, http.StatusOK, job)
+	return nil
+}
@@ -0,0 +1,294 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/cache"
+	"github.com/tiagomelo/golang-waterjug-api/jobs"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+	"github.com/tiagomelo/golang-waterjug-api/web"
+)
+
+// testLog discards the logs of failed requests.
+var testLog = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// createdAt is the time at which jobs of tests are submitted.
+var createdAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type mockPool struct {
+	job       *models.Job
+	submitErr error
+	getErr    error
+	cancelErr error
+}
+
+func (m *mockPool) Submit(ctx context.Context, job *models.Job) error {
+	if m.submitErr != nil {
+		return m.submitErr
+	}
+	job.Status = jobs.StatusQueued
+	job.CreatedAt = createdAt
+	job.UpdatedAt = createdAt
+	return nil
+}
+
+func (m *mockPool) Get(ctx context.Context, id string) (*models.Job, error) {
+	return m.job, m.getErr
+}
+
+func (m *mockPool) Cancel(ctx context.Context, id string) (*models.Job, error) {
+	return m.job, m.cancelErr
+}
+
+func TestCreate(t *testing.T) {
+	testCases := []struct {
+		name               string
+		input              string
+		mockClosure        func(m *mockPool)
+		mockNewJobID       func() (string, error)
+		expectedOutput     string
+		expectedLocation   string
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			input:              `{"x_capacity":3,"y_capacity":5,"z_amount_wanted":4}`,
+			mockClosure:        func(m *mockPool) {},
+			expectedOutput:     `{"id":"42","status":"queued","statusUrl":"/v1/jobs/42","measurement":{"x_capacity":3,"y_capacity":5,"z_amount_wanted":4,"strategy":"bfs"},"createdAt":"2024-01-01T00:00:00Z","updatedAt":"2024-01-01T00:00:00Z"}`,
+			expectedLocation:   "/v1/jobs/42",
+			expectedStatusCode: http.StatusAccepted,
+		},
+		{
+			name:               "with callback",
+			input:              `{"x_capacity":3,"y_capacity":5,"z_amount_wanted":4,"callback_url":"https://example.com/jobs","callback_secret":"It's a Secret to Everybody"}`,
+			mockClosure:        func(m *mockPool) {},
+			expectedOutput:     `{"id":"42","status":"queued","statusUrl":"/v1/jobs/42","measurement":{"x_capacity":3,"y_capacity":5,"z_amount_wanted":4,"strategy":"bfs"},"callbackUrl":"https://example.com/jobs","createdAt":"2024-01-01T00:00:00Z","updatedAt":"2024-01-01T00:00:00Z"}`,
+			expectedLocation:   "/v1/jobs/42",
+			expectedStatusCode: http.StatusAccepted,
+		},
+		{
+			name:               "callback validation error",
+			input:              `{"x_capacity":3,"y_capacity":5,"z_amount_wanted":4,"callback_url":"ftp://example.com/jobs"}`,
+			mockClosure:        func(m *mockPool) {},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#validation-error","title":"Validation Failed","status":400,"detail":"the request has invalid fields","instance":"/v1/jobs","errors":[{"field":"callback_url","error":"callback_url must be a valid HTTP or HTTPS URL"},{"field":"callback_secret","error":"callback_secret is a required field"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "error when decoding payload",
+			input:              `{`,
+			mockClosure:        func(m *mockPool) {},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request","title":"Bad Request","status":400,"detail":"unexpected EOF","instance":"/v1/jobs"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "input validation error",
+			input:              `{"y_capacity":5,"z_amount_wanted":4}`,
+			mockClosure:        func(m *mockPool) {},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#validation-error","title":"Validation Failed","status":400,"detail":"the request has invalid fields","instance":"/v1/jobs","errors":[{"field":"x_capacity","error":"x_capacity is a required field"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "empty goal",
+			input:              `{"x_capacity":3,"y_capacity":5,"goal":{}}`,
+			mockClosure:        func(m *mockPool) {},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request","title":"Bad Request","status":400,"detail":"goal must specify at least one condition","instance":"/v1/jobs"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "error when generating job ID",
+			input: `{"x_capacity":3,"y_capacity":5,"z_amount_wanted":4}`,
+			mockNewJobID: func() (string, error) {
+				return "", errors.New("random error")
+			},
+			mockClosure:        func(m *mockPool) {},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#internal-error","title":"Internal Server Error","status":500,"instance":"/v1/jobs"}`,
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name:  "queue full",
+			input: `{"x_capacity":3,"y_capacity":5,"z_amount_wanted":4}`,
+			mockClosure: func(m *mockPool) {
+				m.submitErr = jobs.ErrQueueFull
+			},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#service-unavailable","title":"Service Unavailable","status":503,"detail":"job queue is full","instance":"/v1/jobs"}`,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:  "error when storing job",
+			input: `{"x_capacity":3,"y_capacity":5,"z_amount_wanted":4}`,
+			mockClosure: func(m *mockPool) {
+				m.submitErr = &cache.Error{Err: errors.New("set error")}
+			},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#service-unavailable","title":"Service Unavailable","status":503,"detail":"cache is unavailable","instance":"/v1/jobs"}`,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+	originalNewJobID := newJobID
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				newJobID = originalNewJobID
+			}()
+			newJobID = func() (string, error) {
+				return "42", nil
+			}
+			if tc.mockNewJobID != nil {
+				newJobID = tc.mockNewJobID
+			}
+			m := new(mockPool)
+			tc.mockClosure(m)
+			req, err := http.NewRequest(http.MethodPost, "/v1/jobs", bytes.NewBuffer([]byte(tc.input)))
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			h := New(m, "bfs")
+			handler := web.Adapt(testLog, h.Create)
+			handler.ServeHTTP(recorder, req)
+			require.Equal(t, tc.expectedStatusCode, recorder.Code)
+			require.Equal(t, tc.expectedOutput, recorder.Body.String())
+			require.Equal(t, tc.expectedLocation, recorder.Header().Get("Location"))
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	testCases := []struct {
+		name               string
+		mockClosure        func(m *mockPool)
+		expectedOutput     string
+		expectedStatusCode int
+	}{
+		{
+			name: "happy path",
+			mockClosure: func(m *mockPool) {
+				m.job = &models.Job{
+					ID:          "42",
+					Status:      jobs.StatusRunning,
+					StatusURL:   "/v1/jobs/42",
+					Measurement: &models.NewMeasurement{XCap: 3, YCap: 5, ZAmountWanted: 4, Strategy: "bfs"},
+					Progress:    &models.JobProgress{Expanded: 1024, Visited: 1100},
+					CreatedAt:   createdAt,
+					UpdatedAt:   createdAt.Add(time.Second),
+				}
+			},
+			expectedOutput:     `{"id":"42","status":"running","statusUrl":"/v1/jobs/42","measurement":{"x_capacity":3,"y_capacity":5,"z_amount_wanted":4,"strategy":"bfs"},"progress":{"expanded":1024,"visited":1100},"createdAt":"2024-01-01T00:00:00Z","updatedAt":"2024-01-01T00:00:01Z"}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: "failed job",
+			mockClosure: func(m *mockPool) {
+				m.job = &models.Job{
+					ID:          "42",
+					Status:      jobs.StatusFailed,
+					StatusURL:   "/v1/jobs/42",
+					Measurement: &models.NewMeasurement{XCap: 2, YCap: 6, ZAmountWanted: 5, Strategy: "bfs"},
+					Error:       []byte(`{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#no-solution","title":"No Solution","status":422,"detail":"no solution: 5 is not a multiple of gcd(2, 6) = 2","instance":"/v1/jobs/42","reason":"gcd_mismatch","x_capacity":2,"y_capacity":6,"z_amount_wanted":5,"gcd":2}`),
+					CreatedAt:   createdAt,
+					UpdatedAt:   createdAt,
+				}
+			},
+			expectedOutput:     `{"id":"42","status":"failed","statusUrl":"/v1/jobs/42","measurement":{"x_capacity":2,"y_capacity":6,"z_amount_wanted":5,"strategy":"bfs"},"error":{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#no-solution","title":"No Solution","status":422,"detail":"no solution: 5 is not a multiple of gcd(2, 6) = 2","instance":"/v1/jobs/42","reason":"gcd_mismatch","x_capacity":2,"y_capacity":6,"z_amount_wanted":5,"gcd":2},"createdAt":"2024-01-01T00:00:00Z","updatedAt":"2024-01-01T00:00:00Z"}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: "job not found",
+			mockClosure: func(m *mockPool) {
+				m.getErr = jobs.ErrNotFound
+			},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#not-found","title":"Not Found","status":404,"detail":"job not found","instance":"/v1/jobs/42"}`,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name: "error when retrieving job",
+			mockClosure: func(m *mockPool) {
+				m.getErr = &cache.Error{Err: errors.New("get error")}
+			},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#service-unavailable","title":"Service Unavailable","status":503,"detail":"cache is unavailable","instance":"/v1/jobs/42"}`,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(mockPool)
+			tc.mockClosure(m)
+			req, err := http.NewRequest(http.MethodGet, "/v1/jobs/42", nil)
+			require.NoError(t, err)
+			req = mux.SetURLVars(req, map[string]string{"id": "42"})
+			recorder := httptest.NewRecorder()
+			h := New(m, "bfs")
+			handler := web.Adapt(testLog, h.Get)
+			handler.ServeHTTP(recorder, req)
+			require.Equal(t, tc.expectedStatusCode, recorder.Code)
+			require.Equal(t, tc.expectedOutput, recorder.Body.String())
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	testCases := []struct {
+		name               string
+		mockClosure        func(m *mockPool)
+		expectedOutput     string
+		expectedStatusCode int
+	}{
+		{
+			name: "happy path",
+			mockClosure: func(m *mockPool) {
+				m.job = &models.Job{
+					ID:          "42",
+					Status:      jobs.StatusCanceled,
+					StatusURL:   "/v1/jobs/42",
+					Measurement: &models.NewMeasurement{XCap: 3, YCap: 5, ZAmountWanted: 4, Strategy: "bfs"},
+					CreatedAt:   createdAt,
+					UpdatedAt:   createdAt.Add(time.Second),
+				}
+			},
+			expectedOutput:     `{"id":"42","status":"canceled","statusUrl":"/v1/jobs/42","measurement":{"x_capacity":3,"y_capacity":5,"z_amount_wanted":4,"strategy":"bfs"},"createdAt":"2024-01-01T00:00:00Z","updatedAt":"2024-01-01T00:00:01Z"}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: "finished job",
+			mockClosure: func(m *mockPool) {
+				m.cancelErr = jobs.ErrFinished
+			},
+			expectedOutput:     `{"type":"about:blank","title":"Conflict","status":409,"detail":"job has already finished","instance":"/v1/jobs/42"}`,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name: "job not found",
+			mockClosure: func(m *mockPool) {
+				m.cancelErr = jobs.ErrNotFound
+			},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#not-found","title":"Not Found","status":404,"detail":"job not found","instance":"/v1/jobs/42"}`,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := new(mockPool)
+			tc.mockClosure(m)
+			req, err := http.NewRequest(http.MethodDelete, "/v1/jobs/42", nil)
+			require.NoError(t, err)
+			req = mux.SetURLVars(req, map[string]string{"id": "42"})
+			recorder := httptest.NewRecorder()
+			h := New(m, "bfs")
+			handler := web.Adapt(testLog, h.Delete)
+			handler.ServeHTTP(recorder, req)
+			require.Equal(t, tc.expectedStatusCode, recorder.Code)
+			require.Equal(t, tc.expectedOutput, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package jugs
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/tiagomelo/golang-waterjug-api/measurement"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+	"github.com/tiagomelo/golang-waterjug-api/render"
+	"github.com/tiagomelo/golang-waterjug-api/validate"
+	"github.com/tiagomelo/golang-waterjug-api/web"
+)
+
+// handlers represents HTTP handlers for exploring a pair of jugs.
+type handlers struct{}
+
+// For ease of unit testing.
+var (
+	// stateGraph builds the state graph of a jug pair.
+	stateGraph = measurement.StateGraph
+	// analyze computes the analytics of the states of a jug pair.
+	analyze = measurement.Analyze
+)
+
+// New creates a new handlers instance.
+func New() *handlers {
+	return &handlers{}
+}
+
+// intVar parses the named path variable as an integer.
+func intVar(r *http.Request, name string) (int, error) {
+	v, err := strconv.Atoi(mux.Vars(r)[name])
+	if err != nil {
+		return 0, errors.New(name + " must be an integer")
+	}
+	return v, nil
+}
+
+// parseStateGraphQuery extracts the state graph parameters from the request.
+func parseStateGraphQuery(r *http.Request) (*models.StateGraphQuery, error) {
+	xCap, err := intVar(r, "x")
+	if err != nil {
+		return nil, err
+	}
+	yCap, err := intVar(r, "y")
+	if err != nil {
+		return nil, err
+	}
+	query := &models.StateGraphQuery{
+		XCap:   xCap,
+		YCap:   yCap,
+		Format: r.URL.Query().Get("format"),
+	}
+	if target := r.URL.Query().Get("target"); target != "" {
+		if query.Target, err = strconv.Atoi(target); err != nil {
+			return nil, errors.New("target must be an integer")
+		}
+	}
+	return query, nil
+}
+
+// Graph is an HTTP handler for exporting the state graph of a jug pair
+// as JSON, Graphviz DOT or GraphML. When a target is given, the optimal
+// path for measuring it is highlighted.
+func (h *handlers) Graph(w http.ResponseWriter, r *http.Request) error {
+	query, err := parseStateGraphQuery(r)
+	if err != nil {
+		return web.BadRequest(err)
+	}
+	if err := validate.Check(query); err != nil {
+		return err
+	}
+	graph, err := stateGraph(query.XCap, query.YCap)
+	if err != nil {
+		return err
+	}
+	if query.Target > 0 {
+		// targets that can't be measured are just not highlighted.
+		solution, _ := measurement.Measure(query.XCap, query.YCap, query.Target)
+		measurement.HighlightSolution(graph, solution)
+	}
+	var buf bytes.Buffer
+	switch query.Format {
+	case "dot":
+		if err := render.DOT(&buf, graph); err != nil {
+			return err
+		}
+		web.Respond(w, http.StatusOK, "text/vnd.graphviz", buf.Bytes())
+	case "graphml":
+		if err := render.GraphML(&buf, graph); err != nil {
+			return err
+		}
+		web.Respond(w, http.StatusOK, "application/graphml+xml", buf.Bytes())
+	default:
+		web.RespondWithJson(w, http.StatusOK, graph)
+	}
+	return nil
+}
+
+// parseStepsTableQuery extracts the steps table parameters from the request.
+func parseStepsTableQuery(r *http.Request) (*models.StepsTableQuery, error) {
+	xCap, err := intVar(r, "x")
+	if err != nil {
+		return nil, err
+	}
+	yCap, err := intVar(r, "y")
+	if err != nil {
+		return nil, err
+	}
+	return &models.StepsTableQuery{
+		XCap:   xCap,
+		YCap:   yCap,
+		Format: r.URL.Query().Get("format"),
+	}, nil
+}
+
+// Steps is an HTTP handler for the minimal number of steps needed to
+// measure every target from 1 to the largest capacity, as JSON or CSV.
+// The whole table comes from a single search.
+func (h *handlers) Steps(w http.ResponseWriter, r *http.Request) error {
+	query, err := parseStepsTableQuery(r)
+	if err != nil {
+		return web.BadRequest(err)
+	}
+	if err := validate.Check(query); err != nil {
+		return err
+	}
+	table := measurement.StepsTable(query.XCap, query.YCap)
+	if query.Format != "csv" {
+		web.RespondWithJson(w, http.StatusOK, table)
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := render.CSV(&buf, table); err != nil {
+		return err
+	}
+	web.Respond(w, http.StatusOK, "text/csv", buf.Bytes())
+	return nil
+}
+
+// parseAnalyticsQuery extracts the analytics parameters from the request.
+func parseAnalyticsQuery(r *http.Request) (*models.AnalyticsQuery, error) {
+	xCap, err := intVar(r, "x")
+	if err != nil {
+		return nil, err
+	}
+	yCap, err := intVar(r, "y")
+	if err != nil {
+		return nil, err
+	}
+	return &models.AnalyticsQuery{XCap: xCap, YCap: yCap}, nil
+}
+
+// Analytics is an HTTP handler for analytics over the state graph of a
+// jug pair: reachable states, diameter, eccentricity of the initial state,
+// hardest targets and unreachable states.
+func (h *handlers) Analytics(w http.ResponseWriter, r *http.Request) error {
+	query, err := parseAnalyticsQuery(r)
+	if err != nil {
+		return web.BadRequest(err)
+	}
+	if err := validate.Check(query); err != nil {
+		return err
+	}
+	analytics, err := analyze(query.XCap, query.YCap)
+	if err != nil {
+		return err
+	}
+	web.RespondWithJson(w, http.StatusOK, analytics)
+	return nil
+}
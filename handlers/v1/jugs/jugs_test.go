@@ -0,0 +1,271 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package jugs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/measurement"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+	"github.com/tiagomelo/golang-waterjug-api/web"
+)
+
+// testLog discards the logs of failed requests.
+var testLog = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestGraph(t *testing.T) {
+	testCases := []struct {
+		name                string
+		x                   string
+		y                   string
+		query               string
+		mockStateGraph      func(xMax, yMax int) (*models.Graph, error)
+		expectedOutput      string
+		expectedContentType string
+		expectedStatusCode  int
+	}{
+		{
+			name:                "happy path, json",
+			x:                   "1",
+			y:                   "1",
+			query:               "?target=1",
+			expectedOutput:      "{\"x_capacity\":1,\"y_capacity\":1,\"nodes\":[{\"id\":\"0,0\",\"bucketX\":0,\"bucketY\":0,\"initial\":true,\"onPath\":true},{\"id\":\"1,0\",\"bucketX\":1,\"bucketY\":0,\"onPath\":true},{\"id\":\"0,1\",\"bucketX\":0,\"bucketY\":1},{\"id\":\"1,1\",\"bucketX\":1,\"bucketY\":1}],\"edges\":[{\"from\":\"0,0\",\"to\":\"1,0\",\"action\":\"Fill bucket X\",\"onPath\":true},{\"from\":\"0,0\",\"to\":\"0,1\",\"action\":\"Fill bucket Y\"},{\"from\":\"1,0\",\"to\":\"1,1\",\"action\":\"Fill bucket Y\"},{\"from\":\"1,0\",\"to\":\"0,0\",\"action\":\"Empty bucket X\"},{\"from\":\"1,0\",\"to\":\"0,1\",\"action\":\"Transfer from bucket X to Y\"},{\"from\":\"0,1\",\"to\":\"1,1\",\"action\":\"Fill bucket X\"},{\"from\":\"0,1\",\"to\":\"0,0\",\"action\":\"Empty bucket Y\"},{\"from\":\"0,1\",\"to\":\"1,0\",\"action\":\"Transfer from bucket Y to X\"},{\"from\":\"1,1\",\"to\":\"0,1\",\"action\":\"Empty bucket X\"},{\"from\":\"1,1\",\"to\":\"1,0\",\"action\":\"Empty bucket Y\"}]}",
+			expectedContentType: "application/json",
+			expectedStatusCode:  http.StatusOK,
+		},
+		{
+			name:  "happy path, dot",
+			x:     "1",
+			y:     "1",
+			query: "?format=dot",
+			mockStateGraph: func(xMax, yMax int) (*models.Graph, error) {
+				return &models.Graph{XCap: 1, YCap: 1, Nodes: []*models.GraphNode{{ID: "0,0", Initial: true}}}, nil
+			},
+			expectedOutput:      "digraph jugs {\n  label=\"jugs of capacities 1 and 1\";\n  \"0,0\" [label=\"(0,0)\", shape=doublecircle];\n}\n",
+			expectedContentType: "text/vnd.graphviz",
+			expectedStatusCode:  http.StatusOK,
+		},
+		{
+			name:  "happy path, graphml",
+			x:     "1",
+			y:     "1",
+			query: "?format=graphml",
+			mockStateGraph: func(xMax, yMax int) (*models.Graph, error) {
+				return &models.Graph{XCap: 1, YCap: 1}, nil
+			},
+			expectedOutput:      "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<graphml xmlns=\"http://graphml.graphdrawing.org/xmlns\">\n  <key id=\"bucketX\" for=\"node\" attr.name=\"bucketX\" attr.type=\"int\"></key>\n  <key id=\"bucketY\" for=\"node\" attr.name=\"bucketY\" attr.type=\"int\"></key>\n  <key id=\"initial\" for=\"node\" attr.name=\"initial\" attr.type=\"boolean\"></key>\n  <key id=\"nodeOnPath\" for=\"node\" attr.name=\"onPath\" attr.type=\"boolean\"></key>\n  <key id=\"action\" for=\"edge\" attr.name=\"action\" attr.type=\"string\"></key>\n  <key id=\"edgeOnPath\" for=\"edge\" attr.name=\"onPath\" attr.type=\"boolean\"></key>\n  <graph id=\"jugs\" edgedefault=\"directed\"></graph>\n</graphml>",
+			expectedContentType: "application/graphml+xml",
+			expectedStatusCode:  http.StatusOK,
+		},
+		{
+			name:                "invalid capacity",
+			x:                   "a",
+			y:                   "1",
+			expectedOutput:      "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"x must be an integer\",\"instance\":\"graph\"}",
+			expectedContentType: "application/problem+json",
+			expectedStatusCode:  http.StatusBadRequest,
+		},
+		{
+			name:                "invalid target",
+			x:                   "1",
+			y:                   "1",
+			query:               "?target=a",
+			expectedOutput:      "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"target must be an integer\",\"instance\":\"graph?target=a\"}",
+			expectedContentType: "application/problem+json",
+			expectedStatusCode:  http.StatusBadRequest,
+		},
+		{
+			name:                "input validation error",
+			x:                   "0",
+			y:                   "1",
+			query:               "?format=png",
+			expectedOutput:      "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#validation-error\",\"title\":\"Validation Failed\",\"status\":400,\"detail\":\"the request has invalid fields\",\"instance\":\"graph?format=png\",\"errors\":[{\"field\":\"x_capacity\",\"error\":\"x_capacity is a required field\"},{\"field\":\"format\",\"error\":\"format must be one of [json dot graphml]\"}]}",
+			expectedContentType: "application/problem+json",
+			expectedStatusCode:  http.StatusBadRequest,
+		},
+		{
+			name: "graph too large",
+			x:    "9999",
+			y:    "9998",
+			mockStateGraph: func(xMax, yMax int) (*models.Graph, error) {
+				return nil, fmt.Errorf("%w: more than 2000 reachable states", measurement.ErrGraphTooLarge)
+			},
+			expectedOutput:      "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"state graph too large: more than 2000 reachable states\",\"instance\":\"graph\"}",
+			expectedContentType: "application/problem+json",
+			expectedStatusCode:  http.StatusBadRequest,
+		},
+		{
+			name: "error when building graph",
+			x:    "1",
+			y:    "1",
+			mockStateGraph: func(xMax, yMax int) (*models.Graph, error) {
+				return nil, errors.New("graph error")
+			},
+			expectedOutput:      "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#internal-error\",\"title\":\"Internal Server Error\",\"status\":500,\"instance\":\"graph\"}",
+			expectedContentType: "application/problem+json",
+			expectedStatusCode:  http.StatusInternalServerError,
+		},
+	}
+	originalStateGraph := stateGraph
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				stateGraph = originalStateGraph
+			}()
+			if tc.mockStateGraph != nil {
+				stateGraph = tc.mockStateGraph
+			}
+			req, err := http.NewRequest(http.MethodGet, "graph"+tc.query, nil)
+			require.NoError(t, err)
+			req = mux.SetURLVars(req, map[string]string{"x": tc.x, "y": tc.y})
+			recorder := httptest.NewRecorder()
+			h := New()
+			handler := web.Adapt(testLog, h.Graph)
+			handler.ServeHTTP(recorder, req)
+			require.Equal(t, tc.expectedStatusCode, recorder.Code)
+			require.Equal(t, tc.expectedContentType, recorder.Header().Get("Content-Type"))
+			require.Equal(t, tc.expectedOutput, recorder.Body.String())
+		})
+	}
+}
+
+func TestSteps(t *testing.T) {
+	testCases := []struct {
+		name                string
+		x                   string
+		y                   string
+		query               string
+		expectedOutput      string
+		expectedContentType string
+		expectedStatusCode  int
+	}{
+		{
+			name:                "happy path, json",
+			x:                   "2",
+			y:                   "4",
+			expectedOutput:      "{\"x_capacity\":2,\"y_capacity\":4,\"targets\":[{\"target\":1,\"solvable\":false},{\"target\":2,\"solvable\":true,\"steps\":1},{\"target\":3,\"solvable\":false},{\"target\":4,\"solvable\":true,\"steps\":1}]}",
+			expectedContentType: "application/json",
+			expectedStatusCode:  http.StatusOK,
+		},
+		{
+			name:                "happy path, csv",
+			x:                   "1",
+			y:                   "5",
+			query:               "?format=csv",
+			expectedOutput:      "target,steps\n1,1\n2,4\n3,4\n4,2\n5,1\n",
+			expectedContentType: "text/csv",
+			expectedStatusCode:  http.StatusOK,
+		},
+		{
+			name:                "invalid capacity",
+			x:                   "1",
+			y:                   "b",
+			expectedOutput:      "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"y must be an integer\",\"instance\":\"steps\"}",
+			expectedContentType: "application/problem+json",
+			expectedStatusCode:  http.StatusBadRequest,
+		},
+		{
+			name:                "input validation error",
+			x:                   "10000",
+			y:                   "1",
+			query:               "?format=dot",
+			expectedOutput:      "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#validation-error\",\"title\":\"Validation Failed\",\"status\":400,\"detail\":\"the request has invalid fields\",\"instance\":\"steps?format=dot\",\"errors\":[{\"field\":\"x_capacity\",\"error\":\"x_capacity must be less than 10,000\"},{\"field\":\"format\",\"error\":\"format must be one of [json csv]\"}]}",
+			expectedContentType: "application/problem+json",
+			expectedStatusCode:  http.StatusBadRequest,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "steps"+tc.query, nil)
+			require.NoError(t, err)
+			req = mux.SetURLVars(req, map[string]string{"x": tc.x, "y": tc.y})
+			recorder := httptest.NewRecorder()
+			h := New()
+			handler := web.Adapt(testLog, h.Steps)
+			handler.ServeHTTP(recorder, req)
+			require.Equal(t, tc.expectedStatusCode, recorder.Code)
+			require.Equal(t, tc.expectedContentType, recorder.Header().Get("Content-Type"))
+			require.Equal(t, tc.expectedOutput, recorder.Body.String())
+		})
+	}
+}
+
+func TestAnalytics(t *testing.T) {
+	testCases := []struct {
+		name               string
+		x                  string
+		y                  string
+		mockAnalyze        func(xMax, yMax int) (*models.StateAnalytics, error)
+		expectedOutput     string
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			x:                  "2",
+			y:                  "4",
+			expectedOutput:     "{\"x_capacity\":2,\"y_capacity\":4,\"totalStates\":15,\"reachableStates\":6,\"diameter\":2,\"initialEccentricity\":2,\"hardestTargetSteps\":1,\"hardestTargets\":[2,4],\"unreachableStates\":[{\"bucketX\":0,\"fromBucketY\":1,\"toBucketY\":1},{\"bucketX\":0,\"fromBucketY\":3,\"toBucketY\":3},{\"bucketX\":1,\"fromBucketY\":0,\"toBucketY\":4},{\"bucketX\":2,\"fromBucketY\":1,\"toBucketY\":1},{\"bucketX\":2,\"fromBucketY\":3,\"toBucketY\":3}]}",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "invalid capacity",
+			x:                  "a",
+			y:                  "4",
+			expectedOutput:     "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"x must be an integer\",\"instance\":\"analytics\"}",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "input validation error",
+			x:                  "0",
+			y:                  "4",
+			expectedOutput:     "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#validation-error\",\"title\":\"Validation Failed\",\"status\":400,\"detail\":\"the request has invalid fields\",\"instance\":\"analytics\",\"errors\":[{\"field\":\"x_capacity\",\"error\":\"x_capacity is a required field\"}]}",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "too many states",
+			x:                  "999",
+			y:                  "1000",
+			expectedOutput:     "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"state graph too large: more than 2000 reachable states\",\"instance\":\"analytics\"}",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "error when analyzing",
+			x:    "2",
+			y:    "4",
+			mockAnalyze: func(xMax, yMax int) (*models.StateAnalytics, error) {
+				return nil, errors.New("analytics error")
+			},
+			expectedOutput:     "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#internal-error\",\"title\":\"Internal Server Error\",\"status\":500,\"instance\":\"analytics\"}",
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	originalAnalyze := analyze
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				analyze = originalAnalyze
+			}()
+			if tc.mockAnalyze != nil {
+				analyze = tc.mockAnalyze
+			}
+			req, err := http.NewRequest(http.MethodGet, "analytics", nil)
+			require.NoError(t, err)
+			req = mux.SetURLVars(req, map[string]string{"x": tc.x, "y": tc.y})
+			recorder := httptest.NewRecorder()
+			h := New()
+			handler := web.Adapt(testLog, h.Analytics)
+			handler.ServeHTTP(recorder, req)
+			require.Equal(t, tc.expectedStatusCode, recorder.Code)
+			require.Equal(t, tc.expectedOutput, recorder.Body.String())
+		})
+	}
+}
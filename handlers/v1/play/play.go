@@ -0,0 +1,266 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package play
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/tiagomelo/golang-waterjug-api/measurement"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+	"github.com/tiagomelo/golang-waterjug-api/validate"
+	"github.com/tiagomelo/golang-waterjug-api/web"
+)
+
+// Kinds of messages sent by players.
+const (
+	messageAction = "action"
+	messageHint   = "hint"
+)
+
+// Kinds of events sent to players.
+const (
+	eventState = "state"
+	eventHint  = "hint"
+	eventWin   = "win"
+	eventError = "error"
+)
+
+// Reasons of error events.
+const (
+	reasonBadMessage    = "bad_message"
+	reasonUnknownAction = "unknown_action"
+	reasonIllegalAction = "illegal_action"
+	reasonRateLimited   = "rate_limited"
+)
+
+// MaxMessageSize is the maximum size of a message sent by a player (1 KiB).
+const MaxMessageSize = 1024
+
+// IdleTimeout is the time a game is kept open without any message from its
+// player (2 minutes).
+const IdleTimeout = 2 * time.Minute
+
+// WriteTimeout is the time allowed to write a message to a player (10 seconds).
+const WriteTimeout = 10 * time.Second
+
+// MessageRate is the number of messages a player can send per second, on
+// average.
+const MessageRate = 5
+
+// MessageBurst is the number of messages a player can send at once, after
+// being quiet for a while.
+const MessageBurst = 10
+
+// For ease of unit testing.
+var (
+	now         = time.Now
+	idleTimeout = IdleTimeout
+)
+
+// upgrader turns requests into WebSocket connections. Browsers are only
+// allowed to connect from pages served by the same host. Requests that
+// can't be upgraded are responded to with a problem, like any other failure.
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  MaxMessageSize,
+	WriteBufferSize: MaxMessageSize,
+	Error: func(w http.ResponseWriter, r *http.Request, status int, reason error) {
+		web.RespondWithProblem(w, web.ProblemFor(r, web.WithStatus(status, reason)))
+	},
+}
+
+// handlers represents HTTP handlers for playing the water jug puzzle.
+type handlers struct{}
+
+// New creates a new handlers instance.
+func New() *handlers {
+	return &handlers{}
+}
+
+// Play is an HTTP handler for playing the puzzle given as query parameters
+// over a WebSocket connection. The player sends actions, which are applied
+// with the same rules the solvers follow, and is answered with the resulting
+// state of the jugs or, when the action can't be applied, an error; hints
+// suggest the next action of the shortest way to the goal. Reaching the goal
+// ends the game with a win event. Puzzles without solution are refused
+// before upgrading the connection. Players sending too many messages get
+// errors instead of answers, and idle ones are disconnected.
+func (h *handlers) Play(w http.ResponseWriter, r *http.Request) error {
+	newMeasurement, err := web.ParseMeasurementQuery(r)
+	if err != nil {
+		return web.BadRequest(err)
+	}
+	if err := validate.Check(newMeasurement); err != nil {
+		return err
+	}
+	problem, err := measurement.NewProblem(newMeasurement)
+	if err != nil {
+		return err
+	}
+	_, optimum, err := measurement.Hint(problem, 0, 0)
+	if err != nil {
+		return err
+	}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader has already responded with the problem.
+		return nil
+	}
+	defer conn.Close()
+	g := &game{
+		conn:    conn,
+		problem: problem,
+		optimum: optimum,
+		limiter: newLimiter(MessageRate, MessageBurst),
+	}
+	g.play()
+	return nil
+}
+
+// game is a game being played over a WebSocket connection. It's only
+// accessed by the goroutine serving the connection.
+type game struct {
+	conn    *websocket.Conn
+	problem measurement.Problem
+	optimum int // optimum is the number of steps of the shortest solution.
+	x       int
+	y       int
+	moves   int
+	limiter *limiter
+}
+
+// play sends the initial state of the jugs and answers the player's
+// messages until the game is won, the player leaves or is idle for too long.
+func (g *game) play() {
+	g.conn.SetReadLimit(MaxMessageSize)
+	if g.send(g.event(eventState)) != nil {
+		return
+	}
+	for {
+		g.conn.SetReadDeadline(now().Add(idleTimeout))
+		_, data, err := g.conn.ReadMessage()
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				g.close(websocket.CloseNormalClosure, "idle timeout")
+			}
+			return
+		}
+		event := g.handle(data)
+		if g.send(event) != nil {
+			return
+		}
+		if event.Type == eventWin {
+			g.close(websocket.CloseNormalClosure, "goal reached")
+			return
+		}
+	}
+}
+
+// handle answers the given message from the player.
+func (g *game) handle(data []byte) *models.PlayEvent {
+	if !g.limiter.allow() {
+		return g.fail(reasonRateLimited, errors.New("too many messages"))
+	}
+	var message models.PlayMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return g.fail(reasonBadMessage, err)
+	}
+	switch message.Type {
+	case messageAction:
+		x, y, err := measurement.Apply(g.problem.XCap, g.problem.YCap, g.x, g.y, message.Action)
+		switch {
+		case errors.Is(err, measurement.ErrUnknownAction):
+			return g.fail(reasonUnknownAction, err)
+		case errors.Is(err, measurement.ErrIllegalAction):
+			return g.fail(reasonIllegalAction, err)
+		}
+		g.x, g.y = x, y
+		g.moves++
+		if g.problem.Reached(x, y) {
+			event := g.event(eventWin)
+			event.Action = message.Action
+			event.Optimum = g.optimum
+			return event
+		}
+		event := g.event(eventState)
+		event.Action = message.Action
+		return event
+	case messageHint:
+		// the goal can be reached from any state, since it can from empty jugs.
+		action, remaining, _ := measurement.Hint(g.problem, g.x, g.y)
+		event := g.event(eventHint)
+		event.Action = action
+		event.Remaining = remaining
+		return event
+	}
+	return g.fail(reasonBadMessage, errors.New(`type must be one of "action" or "hint"`))
+}
+
+// event returns an event of the given type reporting the state of the game.
+func (g *game) event(eventType string) *models.PlayEvent {
+	return &models.PlayEvent{
+		Type:    eventType,
+		BucketX: g.x,
+		BucketY: g.y,
+		Moves:   g.moves,
+	}
+}
+
+// fail returns an error event for the given reason.
+func (g *game) fail(reason string, err error) *models.PlayEvent {
+	event := g.event(eventError)
+	event.Reason = reason
+	event.Error = err.Error()
+	return event
+}
+
+// send sends the given event to the player.
+func (g *game) send(event *models.PlayEvent) error {
+	g.conn.SetWriteDeadline(now().Add(WriteTimeout))
+	return g.conn.WriteJSON(event)
+}
+
+// close tells the player why the game ends.
+func (g *game) close(code int, reason string) {
+	g.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), now().Add(WriteTimeout))
+}
+
+// limiter is a token bucket limiting the rate of messages of a player:
+// each message takes a token, and tokens are put back at a steady rate,
+// up to the size of the bucket.
+type limiter struct {
+	rate   float64   // rate is the number of tokens put back per second.
+	burst  float64   // burst is the size of the bucket.
+	tokens float64   // tokens is the number of tokens left when last checked.
+	last   time.Time // last is when the tokens were last checked.
+}
+
+// newLimiter returns a limiter allowing rate messages per second on average,
+// and burst at once, starting with a full bucket.
+func newLimiter(rate, burst int) *limiter {
+	return &limiter{
+		rate:   float64(rate),
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   now(),
+	}
+}
+
+// allow reports whether a message can be handled now, taking a token if so.
+func (l *limiter) allow() bool {
+	t := now()
+	l.tokens = min(l.burst, l.tokens+t.Sub(l.last).Seconds()*l.rate)
+	l.last = t
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
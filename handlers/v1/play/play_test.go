@@ -0,0 +1,265 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package play
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/web"
+)
+
+// testLog discards the logs of failed requests.
+var testLog = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// dial starts a game of the puzzle given as query parameters. The game is
+// over by the time the test's cleanup functions registered before run.
+func dial(t *testing.T, query string) *websocket.Conn {
+	h := New()
+	handler := web.Adapt(testLog, h.Play)
+	var wg sync.WaitGroup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wg.Add(1)
+		defer wg.Done()
+		handler.ServeHTTP(w, r)
+	}))
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"?"+query, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+		wg.Wait()
+		server.Close()
+	})
+	return conn
+}
+
+// receive reads the next event of a game.
+func receive(t *testing.T, conn *websocket.Conn) string {
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	return string(data)
+}
+
+// requireClosed requires the game to be closed with the given code and reason.
+func requireClosed(t *testing.T, conn *websocket.Conn, code int, reason string) {
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _, err := conn.ReadMessage()
+	require.True(t, websocket.IsCloseError(err, code), "unexpected error: %v", err)
+	require.Equal(t, reason, err.(*websocket.CloseError).Text)
+}
+
+func TestPlay(t *testing.T) {
+	testCases := []struct {
+		name               string
+		query              string
+		origin             string
+		expectedOutput     string
+		expectedStatusCode int
+	}{
+		{
+			name:               "not a websocket handshake",
+			query:              "x=1&y=2&z=2",
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request","title":"Bad Request","status":400,"detail":"websocket: the client is not using the websocket protocol: 'upgrade' token not found in 'Connection' header","instance":"/v1/play?x=1&y=2&z=2"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "cross-origin handshake",
+			query:              "x=1&y=2&z=2",
+			origin:             "https://example.com",
+			expectedOutput:     `{"type":"about:blank","title":"Forbidden","status":403,"detail":"websocket: request origin not allowed by Upgrader.CheckOrigin","instance":"/v1/play?x=1&y=2&z=2"}`,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "invalid query parameter",
+			query:              "x=a&y=2&z=2",
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request","title":"Bad Request","status":400,"detail":"x must be an integer","instance":"/v1/play?x=a&y=2&z=2"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "validation error",
+			query:              "y=2&z=2",
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#validation-error","title":"Validation Failed","status":400,"detail":"the request has invalid fields","instance":"/v1/play?y=2&z=2","errors":[{"field":"x_capacity","error":"x_capacity is a required field"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "no solution",
+			query:              "x=2&y=6&z=5",
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#no-solution","title":"No Solution","status":422,"detail":"no solution: 5 is not a multiple of gcd(2, 6) = 2","instance":"/v1/play?x=2&y=6&z=5","reason":"gcd_mismatch","x_capacity":2,"y_capacity":6,"z_amount_wanted":5,"gcd":2}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/v1/play?"+tc.query, nil)
+			require.NoError(t, err)
+			if tc.origin != "" {
+				req.Header.Set("Connection", "Upgrade")
+				req.Header.Set("Upgrade", "websocket")
+				req.Header.Set("Sec-WebSocket-Version", "13")
+				req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+				req.Header.Set("Origin", tc.origin)
+			}
+			recorder := httptest.NewRecorder()
+			h := New()
+			handler := web.Adapt(testLog, h.Play)
+			handler.ServeHTTP(recorder, req)
+			require.Equal(t, tc.expectedStatusCode, recorder.Code)
+			require.Equal(t, tc.expectedOutput, recorder.Body.String())
+		})
+	}
+}
+
+func TestPlayGame(t *testing.T) {
+	testCases := []struct {
+		name           string
+		messages       []string
+		expectedOutput []string
+		expectedWin    bool
+	}{
+		{
+			name: "win",
+			messages: []string{
+				`{"type":"action","action":"Fill bucket Y"}`,
+				`{"type":"action","action":"Transfer from bucket Y to X"}`,
+				`{"type":"action","action":"Empty bucket X"}`,
+				`{"type":"action","action":"Transfer from bucket Y to X"}`,
+				`{"type":"action","action":"Fill bucket Y"}`,
+				`{"type":"action","action":"Transfer from bucket Y to X"}`,
+			},
+			expectedOutput: []string{
+				`{"type":"state","bucketX":0,"bucketY":5,"moves":1,"action":"Fill bucket Y"}`,
+				`{"type":"state","bucketX":3,"bucketY":2,"moves":2,"action":"Transfer from bucket Y to X"}`,
+				`{"type":"state","bucketX":0,"bucketY":2,"moves":3,"action":"Empty bucket X"}`,
+				`{"type":"state","bucketX":2,"bucketY":0,"moves":4,"action":"Transfer from bucket Y to X"}`,
+				`{"type":"state","bucketX":2,"bucketY":5,"moves":5,"action":"Fill bucket Y"}`,
+				`{"type":"win","bucketX":3,"bucketY":4,"moves":6,"action":"Transfer from bucket Y to X","optimum":6}`,
+			},
+			expectedWin: true,
+		},
+		{
+			name: "hints",
+			messages: []string{
+				`{"type":"hint"}`,
+				`{"type":"action","action":"Fill bucket X"}`,
+				`{"type":"hint"}`,
+			},
+			expectedOutput: []string{
+				`{"type":"hint","bucketX":0,"bucketY":0,"moves":0,"action":"Fill bucket Y","remaining":6}`,
+				`{"type":"state","bucketX":3,"bucketY":0,"moves":1,"action":"Fill bucket X"}`,
+				`{"type":"hint","bucketX":3,"bucketY":0,"moves":1,"action":"Fill bucket Y","remaining":7}`,
+			},
+		},
+		{
+			name: "illegal action",
+			messages: []string{
+				`{"type":"action","action":"Empty bucket X"}`,
+			},
+			expectedOutput: []string{
+				`{"type":"error","bucketX":0,"bucketY":0,"moves":0,"reason":"illegal_action","error":"illegal action: \"Empty bucket X\" with X=0 and Y=0"}`,
+			},
+		},
+		{
+			name: "unknown action",
+			messages: []string{
+				`{"type":"action","action":"Drink bucket X"}`,
+			},
+			expectedOutput: []string{
+				`{"type":"error","bucketX":0,"bucketY":0,"moves":0,"reason":"unknown_action","error":"unknown action: \"Drink bucket X\""}`,
+			},
+		},
+		{
+			name: "bad messages",
+			messages: []string{
+				`{"type":"action",`,
+				`{"type":"surrender"}`,
+			},
+			expectedOutput: []string{
+				`{"type":"error","bucketX":0,"bucketY":0,"moves":0,"reason":"bad_message","error":"unexpected end of JSON input"}`,
+				`{"type":"error","bucketX":0,"bucketY":0,"moves":0,"reason":"bad_message","error":"type must be one of \"action\" or \"hint\""}`,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conn := dial(t, "x=3&y=5&z=4")
+			require.JSONEq(t, `{"type":"state","bucketX":0,"bucketY":0,"moves":0}`, receive(t, conn))
+			for i, message := range tc.messages {
+				require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(message)))
+				require.JSONEq(t, tc.expectedOutput[i], receive(t, conn))
+			}
+			if tc.expectedWin {
+				requireClosed(t, conn, websocket.CloseNormalClosure, "goal reached")
+			}
+		})
+	}
+}
+
+func TestPlayRateLimit(t *testing.T) {
+	originalNow := now
+	t.Cleanup(func() {
+		now = originalNow
+	})
+	frozen := time.Now()
+	now = func() time.Time { return frozen }
+	conn := dial(t, "x=3&y=5&z=4")
+	receive(t, conn)
+	for i := 0; i < MessageBurst; i++ {
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"hint"}`)))
+		require.Contains(t, receive(t, conn), `"type":"hint"`)
+	}
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"hint"}`)))
+	require.JSONEq(t, `{"type":"error","bucketX":0,"bucketY":0,"moves":0,"reason":"rate_limited","error":"too many messages"}`, receive(t, conn))
+}
+
+func TestPlayIdleTimeout(t *testing.T) {
+	originalIdleTimeout := idleTimeout
+	t.Cleanup(func() {
+		idleTimeout = originalIdleTimeout
+	})
+	idleTimeout = 50 * time.Millisecond
+	conn := dial(t, "x=3&y=5&z=4")
+	receive(t, conn)
+	requireClosed(t, conn, websocket.CloseNormalClosure, "idle timeout")
+}
+
+func TestPlayMessageTooBig(t *testing.T) {
+	conn := dial(t, "x=3&y=5&z=4")
+	receive(t, conn)
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(strings.Repeat(" ", MaxMessageSize+1))))
+	requireClosed(t, conn, websocket.CloseMessageTooBig, "")
+}
+
+func TestLimiter(t *testing.T) {
+	originalNow := now
+	t.Cleanup(func() {
+		now = originalNow
+	})
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	l := newLimiter(2, 3)
+	for i := 0; i < 3; i++ {
+		require.True(t, l.allow())
+	}
+	require.False(t, l.allow())
+	clock = clock.Add(250 * time.Millisecond)
+	require.False(t, l.allow())
+	clock = clock.Add(250 * time.Millisecond)
+	require.True(t, l.allow())
+	require.False(t, l.allow())
+	clock = clock.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		require.True(t, l.allow())
+	}
+	require.False(t, l.allow())
+}
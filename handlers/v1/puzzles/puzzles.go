@@ -0,0 +1,177 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package puzzles
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/tiagomelo/golang-waterjug-api/cache"
+	"github.com/tiagomelo/golang-waterjug-api/measurement"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+	"github.com/tiagomelo/golang-waterjug-api/puzzle"
+	"github.com/tiagomelo/golang-waterjug-api/validate"
+	"github.com/tiagomelo/golang-waterjug-api/web"
+)
+
+// handlers represents HTTP handlers for water jug puzzles.
+type handlers struct {
+	cache cache.CacheService
+}
+
+// DailyPuzzleExpiration is the cache expiration time for cached daily
+// puzzles (48 hours), so that a puzzle stays cached for its whole date in
+// every time zone.
+const DailyPuzzleExpiration = 48 * time.Hour
+
+// For ease of unit testing.
+var (
+	// jsonDecode decodes a JSON request body into a given struct.
+	jsonDecode = func(r io.Reader, v any) error {
+		return json.NewDecoder(r).Decode(v)
+	}
+	// generatePuzzles generates puzzles according to the given specification.
+	generatePuzzles = puzzle.Generate
+	// dailyPuzzle returns the puzzle of the given date.
+	dailyPuzzle = puzzle.Daily
+	// now returns the current time, used when no date is given.
+	now = time.Now
+	// retrieveDailyPuzzleFromCache retrieves the puzzle of the given date from the cache.
+	retrieveDailyPuzzleFromCache = func(ctx context.Context,
+		cs cache.CacheService, date string) (*models.DailyPuzzle, error) {
+		return cache.RetrieveDailyPuzzle(ctx, cs, date)
+	}
+	// storeDailyPuzzleInCache stores the puzzle of the day in the cache.
+	storeDailyPuzzleInCache = func(ctx context.Context,
+		cs cache.CacheService, puzzle *models.DailyPuzzle, expiration time.Duration) error {
+		return cache.StoreDailyPuzzle(ctx, cs, puzzle, expiration)
+	}
+)
+
+// errBadDate is returned when the requested date is invalid or in the future.
+var errBadDate = errors.New("bad date")
+
+// New creates a new handlers instance with the provided cache service.
+func New(cache cache.CacheService) *handlers {
+	return &handlers{cache: cache}
+}
+
+// Generate is an HTTP handler for generating puzzles whose optimal
+// solution has an exact number of steps.
+func (h *handlers) Generate(w http.ResponseWriter, r *http.Request) error {
+	defer r.Body.Close()
+	var newPuzzles models.NewPuzzles
+	if err := jsonDecode(r.Body, &newPuzzles); err != nil {
+		return web.BadRequest(err)
+	}
+	if err := validate.Check(newPuzzles); err != nil {
+		return err
+	}
+	generated, err := generatePuzzles(&newPuzzles)
+	if errors.Is(err, puzzle.ErrNotEnoughPuzzles) {
+		return web.BadRequest(err)
+	}
+	if err != nil {
+		return err
+	}
+	web.RespondWithJson(w, http.StatusOK, generated)
+	return nil
+}
+
+// puzzleDate parses the "date" query parameter, defaulting to today.
+// Puzzles of future dates are not available.
+func puzzleDate(r *http.Request) (time.Time, error) {
+	today := now().UTC().Truncate(24 * time.Hour)
+	param := r.URL.Query().Get("date")
+	if param == "" {
+		return today, nil
+	}
+	date, err := time.Parse(puzzle.DateLayout, param)
+	if err != nil {
+		return time.Time{}, web.BadRequest(fmt.Errorf(`%w: "%s" is not formatted as YYYY-MM-DD`, errBadDate, param))
+	}
+	if date.After(today) {
+		return time.Time{}, web.BadRequest(fmt.Errorf("%w: puzzle of %s is not available yet", errBadDate, param))
+	}
+	return date, nil
+}
+
+// daily returns the puzzle of the date requested, from the cache when possible.
+func (h *handlers) daily(r *http.Request) (*models.DailyPuzzle, error) {
+	date, err := puzzleDate(r)
+	if err != nil {
+		return nil, err
+	}
+	cachedPuzzle, err := retrieveDailyPuzzleFromCache(r.Context(), h.cache, date.Format(puzzle.DateLayout))
+	if err != nil {
+		return nil, err
+	}
+	if cachedPuzzle != nil {
+		return cachedPuzzle, nil
+	}
+	dp, err := dailyPuzzle(date)
+	if err != nil {
+		return nil, err
+	}
+	if err := storeDailyPuzzleInCache(r.Context(), h.cache, dp, DailyPuzzleExpiration); err != nil {
+		return nil, err
+	}
+	return dp, nil
+}
+
+// Daily is an HTTP handler for the puzzle of the day. Every caller gets
+// the same puzzle for a given date; its solution is only revealed by Verify.
+func (h *handlers) Daily(w http.ResponseWriter, r *http.Request) error {
+	dp, err := h.daily(r)
+	if err != nil {
+		return err
+	}
+	web.RespondWithJson(w, http.StatusOK, dp)
+	return nil
+}
+
+// Verify is an HTTP handler for verifying a procedure submitted to solve
+// the puzzle of the day. The optimal solution is revealed once it is solved.
+func (h *handlers) Verify(w http.ResponseWriter, r *http.Request) error {
+	defer r.Body.Close()
+	var attempt models.DailyAttempt
+	if err := jsonDecode(r.Body, &attempt); err != nil {
+		return web.BadRequest(err)
+	}
+	if err := validate.Check(attempt); err != nil {
+		return err
+	}
+	dp, err := h.daily(r)
+	if err != nil {
+		return err
+	}
+	solved, err := measurement.Replay(measurement.Problem{
+		XCap:   dp.XCap,
+		YCap:   dp.YCap,
+		Target: dp.ZAmountWanted,
+	}, attempt.Actions)
+	if err != nil {
+		return err
+	}
+	verification := &models.DailyVerification{
+		Date:   dp.Date,
+		Solved: solved,
+		Steps:  len(attempt.Actions),
+	}
+	if solved {
+		verification.Optimal = len(attempt.Actions) == dp.Steps
+		verification.Solution, err = measurement.Measure(dp.XCap, dp.YCap, dp.ZAmountWanted)
+		if err != nil {
+			return err
+		}
+	}
+	web.RespondWithJson(w, http.StatusOK, verification)
+	return nil
+}
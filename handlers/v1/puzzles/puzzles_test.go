@@ -0,0 +1,314 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package puzzles
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/cache"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+	"github.com/tiagomelo/golang-waterjug-api/puzzle"
+	"github.com/tiagomelo/golang-waterjug-api/web"
+)
+
+// testLog discards the logs of failed requests.
+var testLog = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestGenerate(t *testing.T) {
+	testCases := []struct {
+		name                string
+		input               string
+		mockJsonDecode      func(r io.Reader, v any) error
+		mockGeneratePuzzles func(spec *models.NewPuzzles) (*models.GeneratedPuzzles, error)
+		expectedOutput      string
+		expectedStatusCode  int
+	}{
+		{
+			name:  "happy path",
+			input: `{"steps":6,"min_capacity":3,"max_capacity":20,"seed":42}`,
+			mockGeneratePuzzles: func(spec *models.NewPuzzles) (*models.GeneratedPuzzles, error) {
+				return &models.GeneratedPuzzles{
+					Seed:    *spec.Seed,
+					Puzzles: []*models.Puzzle{{XCap: 3, YCap: 5, ZAmountWanted: 4, Steps: spec.Steps}},
+				}, nil
+			},
+			expectedOutput:     `{"seed":42,"puzzles":[{"x_capacity":3,"y_capacity":5,"z_amount_wanted":4,"steps":6}]}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:  "error when decoding payload",
+			input: ``,
+			mockJsonDecode: func(r io.Reader, v any) error {
+				return errors.New("decode error")
+			},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request","title":"Bad Request","status":400,"detail":"decode error","instance":"generate"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "input validation error",
+			input:              `{"steps":1,"min_capacity":5,"max_capacity":5}`,
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#validation-error","title":"Validation Failed","status":400,"detail":"the request has invalid fields","instance":"generate","errors":[{"field":"steps","error":"steps must be 2 or greater"},{"field":"max_capacity","error":"max_capacity must be greater than min_capacity"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "not enough puzzles",
+			input: `{"steps":50,"min_capacity":1,"max_capacity":3}`,
+			mockGeneratePuzzles: func(spec *models.NewPuzzles) (*models.GeneratedPuzzles, error) {
+				return nil, fmt.Errorf("%w: found 0 of 1", puzzle.ErrNotEnoughPuzzles)
+			},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request","title":"Bad Request","status":400,"detail":"not enough puzzles: found 0 of 1","instance":"generate"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "error when generating puzzles",
+			input: `{"steps":6,"min_capacity":3,"max_capacity":20}`,
+			mockGeneratePuzzles: func(spec *models.NewPuzzles) (*models.GeneratedPuzzles, error) {
+				return nil, errors.New("generate error")
+			},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#internal-error","title":"Internal Server Error","status":500,"instance":"generate"}`,
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+	originalJsonDecode := jsonDecode
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				jsonDecode = originalJsonDecode
+			}()
+			if tc.mockJsonDecode != nil {
+				jsonDecode = tc.mockJsonDecode
+			}
+			generatePuzzles = tc.mockGeneratePuzzles
+			req, err := http.NewRequest(http.MethodPost, "generate", bytes.NewBuffer([]byte(tc.input)))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+			h := New(nil)
+			handler := web.Adapt(testLog, h.Generate)
+			handler.ServeHTTP(recorder, req)
+			require.Equal(t, tc.expectedStatusCode, recorder.Code)
+			require.Equal(t, tc.expectedOutput, recorder.Body.String())
+		})
+	}
+}
+
+func TestDaily(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		query                   string
+		mockRetrieveDailyPuzzle func(ctx context.Context, cs cache.CacheService, date string) (*models.DailyPuzzle, error)
+		mockDailyPuzzle         func(date time.Time) (*models.DailyPuzzle, error)
+		mockStoreDailyPuzzle    func(ctx context.Context, cs cache.CacheService, puzzle *models.DailyPuzzle, expiration time.Duration) error
+		expectedOutput          string
+		expectedStatusCode      int
+	}{
+		{
+			name:  "happy path",
+			query: "?date=2024-01-01",
+			mockRetrieveDailyPuzzle: func(ctx context.Context, cs cache.CacheService, date string) (*models.DailyPuzzle, error) {
+				return nil, nil
+			},
+			mockDailyPuzzle: puzzle.Daily,
+			mockStoreDailyPuzzle: func(ctx context.Context, cs cache.CacheService, puzzle *models.DailyPuzzle, expiration time.Duration) error {
+				return nil
+			},
+			expectedOutput:     `{"date":"2024-01-01","difficulty":"easy","x_capacity":13,"y_capacity":23,"z_amount_wanted":3,"steps":4}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: "happy path, today's puzzle from cache",
+			mockRetrieveDailyPuzzle: func(ctx context.Context, cs cache.CacheService, date string) (*models.DailyPuzzle, error) {
+				return &models.DailyPuzzle{
+					Date:       date,
+					Difficulty: puzzle.DifficultyMedium,
+					Puzzle:     models.Puzzle{XCap: 3, YCap: 5, ZAmountWanted: 4, Steps: 6},
+				}, nil
+			},
+			expectedOutput:     `{"date":"2024-01-10","difficulty":"medium","x_capacity":3,"y_capacity":5,"z_amount_wanted":4,"steps":6}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "invalid date",
+			query:              "?date=01/01/2024",
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request","title":"Bad Request","status":400,"detail":"bad date: \"01/01/2024\" is not formatted as YYYY-MM-DD","instance":"daily?date=01/01/2024"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "future date",
+			query:              "?date=2024-01-11",
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request","title":"Bad Request","status":400,"detail":"bad date: puzzle of 2024-01-11 is not available yet","instance":"daily?date=2024-01-11"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "error when retrieving puzzle from cache",
+			query: "?date=2024-01-01",
+			mockRetrieveDailyPuzzle: func(ctx context.Context, cs cache.CacheService, date string) (*models.DailyPuzzle, error) {
+				return nil, &cache.Error{Err: errors.New("retrieve error")}
+			},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#service-unavailable","title":"Service Unavailable","status":503,"detail":"cache is unavailable","instance":"daily?date=2024-01-01"}`,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:  "error when generating puzzle",
+			query: "?date=2024-01-01",
+			mockRetrieveDailyPuzzle: func(ctx context.Context, cs cache.CacheService, date string) (*models.DailyPuzzle, error) {
+				return nil, nil
+			},
+			mockDailyPuzzle: func(date time.Time) (*models.DailyPuzzle, error) {
+				return nil, errors.New("generate error")
+			},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#internal-error","title":"Internal Server Error","status":500,"instance":"daily?date=2024-01-01"}`,
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name:  "error when storing puzzle in cache",
+			query: "?date=2024-01-01",
+			mockRetrieveDailyPuzzle: func(ctx context.Context, cs cache.CacheService, date string) (*models.DailyPuzzle, error) {
+				return nil, nil
+			},
+			mockDailyPuzzle: puzzle.Daily,
+			mockStoreDailyPuzzle: func(ctx context.Context, cs cache.CacheService, puzzle *models.DailyPuzzle, expiration time.Duration) error {
+				return &cache.Error{Err: errors.New("store error")}
+			},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#service-unavailable","title":"Service Unavailable","status":503,"detail":"cache is unavailable","instance":"daily?date=2024-01-01"}`,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+	originalNow := now
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				now = originalNow
+			}()
+			now = func() time.Time {
+				return time.Date(2024, time.January, 10, 15, 30, 0, 0, time.UTC)
+			}
+			retrieveDailyPuzzleFromCache = tc.mockRetrieveDailyPuzzle
+			dailyPuzzle = tc.mockDailyPuzzle
+			storeDailyPuzzleInCache = tc.mockStoreDailyPuzzle
+			req, err := http.NewRequest(http.MethodGet, "daily"+tc.query, nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			h := New(nil)
+			handler := web.Adapt(testLog, h.Daily)
+			handler.ServeHTTP(recorder, req)
+			require.Equal(t, tc.expectedStatusCode, recorder.Code)
+			require.Equal(t, tc.expectedOutput, recorder.Body.String())
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	const optimalSolution = `{"solution":[{"step":1,"bucketX":0,"bucketY":5,"action":"Fill bucket Y"},` +
+		`{"step":2,"bucketX":3,"bucketY":2,"action":"Transfer from bucket Y to X"},` +
+		`{"step":3,"bucketX":0,"bucketY":2,"action":"Empty bucket X"},` +
+		`{"step":4,"bucketX":2,"bucketY":0,"action":"Transfer from bucket Y to X"},` +
+		`{"step":5,"bucketX":2,"bucketY":5,"action":"Fill bucket Y"},` +
+		`{"step":6,"bucketX":3,"bucketY":4,"action":"Transfer from bucket Y to X","status":"Solved"}]}`
+	testCases := []struct {
+		name                    string
+		input                   string
+		mockJsonDecode          func(r io.Reader, v any) error
+		mockRetrieveDailyPuzzle func(ctx context.Context, cs cache.CacheService, date string) (*models.DailyPuzzle, error)
+		expectedOutput          string
+		expectedStatusCode      int
+	}{
+		{
+			name: "optimal procedure",
+			input: `{"actions":["Fill bucket Y","Transfer from bucket Y to X","Empty bucket X",` +
+				`"Transfer from bucket Y to X","Fill bucket Y","Transfer from bucket Y to X"]}`,
+			expectedOutput:     `{"date":"2024-01-10","solved":true,"steps":6,"optimal":true,"solution":` + optimalSolution + `}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: "non-optimal procedure",
+			input: `{"actions":["Fill bucket X","Empty bucket X","Fill bucket Y","Transfer from bucket Y to X","Empty bucket X",` +
+				`"Transfer from bucket Y to X","Fill bucket Y","Transfer from bucket Y to X"]}`,
+			expectedOutput:     `{"date":"2024-01-10","solved":true,"steps":8,"optimal":false,"solution":` + optimalSolution + `}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "procedure that does not solve the puzzle",
+			input:              `{"actions":["Fill bucket Y","Transfer from bucket Y to X"]}`,
+			expectedOutput:     `{"date":"2024-01-10","solved":false,"steps":2,"optimal":false}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "illegal action",
+			input:              `{"actions":["Fill bucket Y","Fill bucket Y"]}`,
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request","title":"Bad Request","status":400,"detail":"step 2: illegal action: \"Fill bucket Y\" with X=0 and Y=5","instance":"daily/verify"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "error when decoding payload",
+			input: ``,
+			mockJsonDecode: func(r io.Reader, v any) error {
+				return errors.New("decode error")
+			},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request","title":"Bad Request","status":400,"detail":"decode error","instance":"daily/verify"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "input validation error",
+			input:              `{"actions":[]}`,
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#validation-error","title":"Validation Failed","status":400,"detail":"the request has invalid fields","instance":"daily/verify","errors":[{"field":"actions","error":"actions must contain at least 1 item"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "error when retrieving puzzle from cache",
+			input: `{"actions":["Fill bucket Y"]}`,
+			mockRetrieveDailyPuzzle: func(ctx context.Context, cs cache.CacheService, date string) (*models.DailyPuzzle, error) {
+				return nil, &cache.Error{Err: errors.New("retrieve error")}
+			},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#service-unavailable","title":"Service Unavailable","status":503,"detail":"cache is unavailable","instance":"daily/verify"}`,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+	originalJsonDecode := jsonDecode
+	originalNow := now
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				jsonDecode = originalJsonDecode
+				now = originalNow
+			}()
+			if tc.mockJsonDecode != nil {
+				jsonDecode = tc.mockJsonDecode
+			}
+			now = func() time.Time {
+				return time.Date(2024, time.January, 10, 15, 30, 0, 0, time.UTC)
+			}
+			retrieveDailyPuzzleFromCache = tc.mockRetrieveDailyPuzzle
+			if tc.mockRetrieveDailyPuzzle == nil {
+				retrieveDailyPuzzleFromCache = func(ctx context.Context, cs cache.CacheService, date string) (*models.DailyPuzzle, error) {
+					return &models.DailyPuzzle{
+						Date:       date,
+						Difficulty: puzzle.DifficultyMedium,
+						Puzzle:     models.Puzzle{XCap: 3, YCap: 5, ZAmountWanted: 4, Steps: 6},
+					}, nil
+				}
+			}
+			req, err := http.NewRequest(http.MethodPost, "daily/verify", bytes.NewBuffer([]byte(tc.input)))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+			h := New(nil)
+			handler := web.Adapt(testLog, h.Verify)
+			handler.ServeHTTP(recorder, req)
+			require.Equal(t, tc.expectedStatusCode, recorder.Code)
+			require.Equal(t, tc.expectedOutput, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package recommend
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/tiagomelo/golang-waterjug-api/measurement"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+	"github.com/tiagomelo/golang-waterjug-api/validate"
+	"github.com/tiagomelo/golang-waterjug-api/web"
+)
+
+// handlers represents HTTP handlers for recommending jugs.
+type handlers struct{}
+
+// For ease of unit testing.
+var (
+	// jsonDecode decodes a JSON request body into a given struct.
+	jsonDecode = func(r io.Reader, v any) error {
+		return json.NewDecoder(r).Decode(v)
+	}
+)
+
+// New creates a new handlers instance.
+func New() *handlers {
+	return &handlers{}
+}
+
+// Recommend is an HTTP handler for ranking every pair of jugs from an
+// inventory that can measure the wanted amount, attaching the optimal
+// solution with the best pair.
+func (h *handlers) Recommend(w http.ResponseWriter, r *http.Request) error {
+	defer r.Body.Close()
+	var newRecommendation models.NewRecommendation
+	if err := jsonDecode(r.Body, &newRecommendation); err != nil {
+		return web.BadRequest(err)
+	}
+	if err := validate.Check(newRecommendation); err != nil {
+		return err
+	}
+	recommendation, err := measurement.Recommend(newRecommendation.Inventory,
+		newRecommendation.ZAmountWanted, newRecommendation.RankBy)
+	if err != nil {
+		return err
+	}
+	web.RespondWithJson(w, http.StatusOK, recommendation)
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package recommend
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/web"
+)
+
+// testLog discards the logs of failed requests.
+var testLog = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestRecommend(t *testing.T) {
+	testCases := []struct {
+		name               string
+		input              string
+		mockJsonDecode     func(r io.Reader, v any) error
+		expectedOutput     string
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			input:              `{"inventory":[2,7,4],"z_amount_wanted":1,"rank_by":"water"}`,
+			expectedOutput:     `{"rankBy":"water","pairs":[{"x_capacity":2,"y_capacity":7,"steps":6,"waterUsed":7,"score":2.5},{"x_capacity":4,"y_capacity":7,"steps":4,"waterUsed":8,"score":2.14}],"solution":{"solution":[{"step":1,"bucketX":0,"bucketY":7,"action":"Fill bucket Y"},{"step":2,"bucketX":2,"bucketY":5,"action":"Transfer from bucket Y to X"},{"step":3,"bucketX":0,"bucketY":5,"action":"Empty bucket X"},{"step":4,"bucketX":2,"bucketY":3,"action":"Transfer from bucket Y to X"},{"step":5,"bucketX":0,"bucketY":3,"action":"Empty bucket X"},{"step":6,"bucketX":2,"bucketY":1,"action":"Transfer from bucket Y to X","status":"Solved"}]}}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:  "error when decoding payload",
+			input: ``,
+			mockJsonDecode: func(r io.Reader, v any) error {
+				return errors.New("decode error")
+			},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request","title":"Bad Request","status":400,"detail":"decode error","instance":"recommend"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "input validation error",
+			input:              `{"inventory":[3],"z_amount_wanted":1,"rank_by":"price"}`,
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#validation-error","title":"Validation Failed","status":400,"detail":"the request has invalid fields","instance":"recommend","errors":[{"field":"inventory","error":"inventory must contain at least 2 items"},{"field":"rank_by","error":"rank_by must be one of [steps water score]"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "inventory too large",
+			input:              `{"inventory":[9999,9998,9997,9996,9995,9994,9993,9992,9991,9990,9989],"z_amount_wanted":1}`,
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#validation-error","title":"Validation Failed","status":400,"detail":"the request has invalid fields","instance":"recommend","errors":[{"field":"inventory","error":"inventory cannot have more than 100000 as the number of jugs times the largest capacity"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "no solution",
+			input:              `{"inventory":[3,4,6],"z_amount_wanted":5}`,
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#no-solution","title":"No Solution","status":422,"detail":"no solution: no pair of jugs from the inventory can measure 5","instance":"recommend","reason":"no_pair","z_amount_wanted":5,"pairs":[{"reason":"target_exceeds_capacity","x_capacity":3,"y_capacity":4,"z_amount_wanted":5},{"reason":"gcd_mismatch","x_capacity":3,"y_capacity":6,"z_amount_wanted":5,"gcd":3},{"reason":"gcd_mismatch","x_capacity":4,"y_capacity":6,"z_amount_wanted":5,"gcd":2}]}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+	}
+	originalJsonDecode := jsonDecode
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				jsonDecode = originalJsonDecode
+			}()
+			if tc.mockJsonDecode != nil {
+				jsonDecode = tc.mockJsonDecode
+			}
+			req, err := http.NewRequest(http.MethodPost, "recommend", bytes.NewBuffer([]byte(tc.input)))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+			h := New()
+			handler := web.Adapt(testLog, h.Recommend)
+			handler.ServeHTTP(recorder, req)
+			require.Equal(t, tc.expectedStatusCode, recorder.Code)
+			require.Equal(t, tc.expectedOutput, recorder.Body.String())
+		})
+	}
+}
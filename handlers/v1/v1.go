@@ -10,32 +10,71 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/tiagomelo/golang-waterjug-api/cache"
+	"github.com/tiagomelo/golang-waterjug-api/handlers/v1/jobs"
+	"github.com/tiagomelo/golang-waterjug-api/handlers/v1/jugs"
+	"github.com/tiagomelo/golang-waterjug-api/handlers/v1/play"
+	"github.com/tiagomelo/golang-waterjug-api/handlers/v1/puzzles"
+	"github.com/tiagomelo/golang-waterjug-api/handlers/v1/recommend"
 	"github.com/tiagomelo/golang-waterjug-api/handlers/v1/waterjug"
+	asyncjobs "github.com/tiagomelo/golang-waterjug-api/jobs"
 	"github.com/tiagomelo/golang-waterjug-api/middleware"
+	"github.com/tiagomelo/golang-waterjug-api/web"
 )
 
-// Config struct holds the database connection and logger.
+// Config struct holds the database connection, logger, default solving strategy
+// and the pool running asynchronous jobs, without which /v1/jobs is not served.
 type Config struct {
-	Cache cache.CacheService
-	Log   *slog.Logger
+	Cache    cache.CacheService
+	Log      *slog.Logger
+	Strategy string
+	Jobs     *asyncjobs.Pool
 }
 
 // Routes initializes and returns a new router with configured routes.
 func Routes(c *Config) *mux.Router {
 	router := mux.NewRouter()
-	initializeRoutes(c.Cache, router)
+	initializeRoutes(c, router)
 	router.Use(
 		func(h http.Handler) http.Handler {
 			return middleware.Logger(c.Log, h)
 		},
 		middleware.Compress,
-		middleware.PanicRecovery,
+		func(h http.Handler) http.Handler {
+			return middleware.PanicRecovery(c.Log, h)
+		},
 	)
+	router.NotFoundHandler = http.HandlerFunc(web.NotFound)
+	router.MethodNotAllowedHandler = http.HandlerFunc(web.MethodNotAllowed)
 	return router
 }
 
 // initializeRoutes sets up the routes.
-func initializeRoutes(cache cache.CacheService, router *mux.Router) {
-	waterjugHandlers := waterjug.New(cache)
-	router.HandleFunc("/v1/measure", waterjugHandlers.Measure).Methods(http.MethodPost)
+func initializeRoutes(c *Config, router *mux.Router) {
+	handle := func(h web.Handler) http.Handler {
+		return web.Adapt(c.Log, h)
+	}
+	waterjugHandlers := waterjug.New(c.Cache, c.Strategy)
+	router.Handle("/v1/measure", handle(waterjugHandlers.Measure)).Methods(http.MethodPost)
+	router.Handle("/v1/measure", handle(waterjugHandlers.MeasureQuery)).Methods(http.MethodGet)
+	router.Handle("/v1/measure/batch", handle(waterjugHandlers.Batch)).Methods(http.MethodPost)
+	router.Handle("/v1/measure/stream", handle(waterjugHandlers.Stream)).Methods(http.MethodPost)
+	router.Handle("/v1/measure/stream", handle(waterjugHandlers.Events)).Methods(http.MethodGet)
+	jugsHandlers := jugs.New()
+	router.Handle("/v1/jugs/{x}/{y}/graph", handle(jugsHandlers.Graph)).Methods(http.MethodGet)
+	router.Handle("/v1/jugs/{x}/{y}/steps", handle(jugsHandlers.Steps)).Methods(http.MethodGet)
+	router.Handle("/v1/jugs/{x}/{y}/analytics", handle(jugsHandlers.Analytics)).Methods(http.MethodGet)
+	puzzlesHandlers := puzzles.New(c.Cache)
+	router.Handle("/v1/puzzles/generate", handle(puzzlesHandlers.Generate)).Methods(http.MethodPost)
+	router.Handle("/v1/puzzles/daily", handle(puzzlesHandlers.Daily)).Methods(http.MethodGet)
+	router.Handle("/v1/puzzles/daily/verify", handle(puzzlesHandlers.Verify)).Methods(http.MethodPost)
+	recommendHandlers := recommend.New()
+	router.Handle("/v1/recommend", handle(recommendHandlers.Recommend)).Methods(http.MethodPost)
+	playHandlers := play.New()
+	router.Handle("/v1/play", handle(playHandlers.Play)).Methods(http.MethodGet)
+	if c.Jobs != nil {
+		jobsHandlers := jobs.New(c.Jobs, c.Strategy)
+		router.Handle("/v1/jobs", handle(jobsHandlers.Create)).Methods(http.MethodPost)
+		router.Handle("/v1/jobs/{id}", handle(jobsHandlers.Get)).Methods(http.MethodGet)
+		router.Handle("/v1/jobs/{id}", handle(jobsHandlers.Delete)).Methods(http.MethodDelete)
+	}
 }
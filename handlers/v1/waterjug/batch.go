@@ -0,0 +1,153 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package waterjug
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+	"github.com/tiagomelo/golang-waterjug-api/web"
+)
+
+// MaxBatchSize is the maximum number of measurements in a batch.
+const MaxBatchSize = 1000
+
+// BatchWorkers is the number of measurements of a batch solved concurrently.
+const BatchWorkers = 8
+
+// batchItem is the outcome of measuring one item of a batch.
+type batchItem struct {
+	index    int
+	solution *models.Solution
+	err      error
+}
+
+// batchResult is the result of one measurement of a batch: either its
+// solution or the problem that prevented it from being solved.
+type batchResult struct {
+	Index    int              `json:"index"`              // Index represents the position of the measurement in the batch.
+	Solution *models.Solution `json:"solution,omitempty"` // Solution represents the solution, when found.
+	Error    *web.Problem     `json:"error,omitempty"`    // Error represents the problem, when not.
+}
+
+// batchInput is a measurement of a batch, or the error that prevented it
+// from being read.
+type batchInput struct {
+	index          int
+	newMeasurement *models.NewMeasurement
+	err            error
+}
+
+// measureStream measures the given inputs concurrently, at most BatchWorkers
+// at a time, each the way Measure does, using the cache. Outcomes are sent
+// as soon as they're known, in no particular order. The channel is closed
+// once inputs is closed and every input is measured or, when ctx is done,
+// as soon as the inputs being measured are.
+func (h *handlers) measureStream(ctx context.Context, inputs <-chan batchInput) <-chan batchItem {
+	outcomes := make(chan batchItem)
+	var wg sync.WaitGroup
+	for i := 0; i < BatchWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				var input batchInput
+				var ok bool
+				select {
+				case input, ok = <-inputs:
+					if !ok {
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+				item := batchItem{index: input.index, err: input.err}
+				if item.err == nil {
+					item.solution, item.err = h.measureItem(ctx, input.newMeasurement)
+				}
+				select {
+				case outcomes <- item:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		defer close(outcomes)
+		wg.Wait()
+	}()
+	return outcomes
+}
+
+// measureItem measures a single item of a batch.
+func (h *handlers) measureItem(ctx context.Context, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+	problem, err := h.prepare(newMeasurement)
+	if err != nil {
+		return nil, err
+	}
+	return h.measure(ctx, newMeasurement, problem)
+}
+
+// measureBatch measures the given items like measureStream does.
+func (h *handlers) measureBatch(ctx context.Context, items []models.NewMeasurement) <-chan batchItem {
+	inputs := make(chan batchInput)
+	go func() {
+		defer close(inputs)
+		for i := range items {
+			select {
+			case inputs <- batchInput{index: i, newMeasurement: &items[i]}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return h.measureStream(ctx, inputs)
+}
+
+// decodeBatch decodes the measurements of a batch from the request body.
+func decodeBatch(r *http.Request) ([]models.NewMeasurement, error) {
+	var items []models.NewMeasurement
+	if err := jsonDecode(r.Body, &items); err != nil {
+		return nil, web.BadRequest(err)
+	}
+	if len(items) == 0 || len(items) > MaxBatchSize {
+		return nil, web.BadRequest(fmt.Errorf("a batch must have between 1 and %d measurements", MaxBatchSize))
+	}
+	return items, nil
+}
+
+// Batch is an HTTP handler for measuring a batch of water jug solutions
+// in a single request. The results are in the same order as the measurements,
+// each with either the solution or the problem that prevented it from being
+// solved, so that an invalid or unsolvable measurement doesn't fail the others.
+func (h *handlers) Batch(w http.ResponseWriter, r *http.Request) error {
+	defer r.Body.Close()
+	items, err := decodeBatch(r)
+	if err != nil {
+		return err
+	}
+	results := make([]*batchResult, len(items))
+	for item := range h.measureBatch(r.Context(), items) {
+		results[item.index] = newBatchResult(r, item)
+	}
+	if err := r.Context().Err(); err != nil {
+		return err
+	}
+	web.RespondWithJson(w, http.StatusOK, results)
+	return nil
+}
+
+// newBatchResult turns the outcome of measuring an item into its result.
+func newBatchResult(r *http.Request, item batchItem) *batchResult {
+	result := &batchResult{Index: item.index, Solution: item.solution}
+	if item.err != nil {
+		result.Error = web.ProblemFor(r, item.err)
+	}
+	return result
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package waterjug
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/cache"
+	"github.com/tiagomelo/golang-waterjug-api/measurement"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+	"github.com/tiagomelo/golang-waterjug-api/web"
+)
+
+// mockBatchCache replaces the cache functions with ones that find nothing
+// and store nothing.
+func mockBatchCache() {
+	retrieveSolutionFromCache = func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+		return nil, nil
+	}
+	storeSolutionInCache = func(ctx context.Context, cs cache.CacheService, measurement *models.NewMeasurement, solution *models.Solution, expiration time.Duration) error {
+		return nil
+	}
+	retrieveTreeFromCache = func(ctx context.Context, cs cache.CacheService, xCap, yCap int) (*measurement.Tree, error) {
+		return nil, nil
+	}
+	storeTreeInCache = func(ctx context.Context, cs cache.CacheService, xCap, yCap int, tree *measurement.Tree, expiration time.Duration) error {
+		return nil
+	}
+}
+
+func TestBatch(t *testing.T) {
+	testCases := []struct {
+		name                          string
+		input                         string
+		mockRetrieveSolutionFromCache func(ctx context.Context, cs cache.CacheService,
+			newMeasurement *models.NewMeasurement) (*models.Solution, error)
+		expectedOutput     string
+		expectedStatusCode int
+	}{
+		{
+			name:               "happy path",
+			input:              `[{"x_capacity":1,"y_capacity":2,"z_amount_wanted":2},{"x_capacity":2,"y_capacity":6,"z_amount_wanted":5},{"x_capacity":1,"y_capacity":3,"z_amount_wanted":2,"strategy":"math"},{"y_capacity":2,"z_amount_wanted":1}]`,
+			expectedOutput:     `[{"index":0,"solution":{"solution":[{"step":1,"bucketX":0,"bucketY":2,"action":"Fill bucket Y","status":"Solved"}],"strategy":"bfs"}},{"index":1,"error":{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#no-solution","title":"No Solution","status":422,"detail":"no solution: 5 is not a multiple of gcd(2, 6) = 2","instance":"measure/batch","reason":"gcd_mismatch","x_capacity":2,"y_capacity":6,"z_amount_wanted":5,"gcd":2}},{"index":2,"solution":{"solution":[{"step":1,"bucketX":0,"bucketY":3,"action":"Fill bucket Y"},{"step":2,"bucketX":1,"bucketY":2,"action":"Transfer from bucket Y to X","status":"Solved"}],"strategy":"math"}},{"index":3,"error":{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#validation-error","title":"Validation Failed","status":400,"detail":"the request has invalid fields","instance":"measure/batch","errors":[{"field":"x_capacity","error":"x_capacity is a required field"}]}}]`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:  "error when retrieving solution from cache",
+			input: `[{"x_capacity":1,"y_capacity":2,"z_amount_wanted":2}]`,
+			mockRetrieveSolutionFromCache: func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+				return nil, &cache.Error{Err: errors.New("get error")}
+			},
+			expectedOutput:     `[{"index":0,"error":{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#service-unavailable","title":"Service Unavailable","status":503,"detail":"cache is unavailable","instance":"measure/batch"}}]`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "error when decoding payload",
+			input:              `{"x_capacity":1,"y_capacity":2,"z_amount_wanted":2}`,
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request","title":"Bad Request","status":400,"detail":"json: cannot unmarshal object into Go value of type []models.NewMeasurement","instance":"measure/batch"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "empty batch",
+			input:              `[]`,
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request","title":"Bad Request","status":400,"detail":"a batch must have between 1 and 1000 measurements","instance":"measure/batch"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "batch too large",
+			input:              "[" + strings.Repeat(`{"x_capacity":1,"y_capacity":2,"z_amount_wanted":2},`, MaxBatchSize) + `{"x_capacity":1,"y_capacity":2,"z_amount_wanted":2}]`,
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request","title":"Bad Request","status":400,"detail":"a batch must have between 1 and 1000 measurements","instance":"measure/batch"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockBatchCache()
+			if tc.mockRetrieveSolutionFromCache != nil {
+				retrieveSolutionFromCache = tc.mockRetrieveSolutionFromCache
+			}
+			req, err := http.NewRequest(http.MethodPost, "measure/batch", bytes.NewBuffer([]byte(tc.input)))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+			h := New(nil, measurement.DefaultStrategy)
+			handler := web.Adapt(testLog, h.Batch)
+			handler.ServeHTTP(recorder, req)
+			require.Equal(t, tc.expectedStatusCode, recorder.Code)
+			require.Equal(t, tc.expectedOutput, recorder.Body.String())
+		})
+	}
+}
+
+func TestMeasureBatchWorkers(t *testing.T) {
+	mockBatchCache()
+	var (
+		mu                  sync.Mutex
+		running, maxRunning int
+	)
+	retrieveSolutionFromCache = func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+		mu.Lock()
+		running++
+		maxRunning = max(maxRunning, running)
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil, nil
+	}
+	items := make([]models.NewMeasurement, 5*BatchWorkers)
+	for i := range items {
+		items[i] = models.NewMeasurement{XCap: 3, YCap: 5, ZAmountWanted: i%5 + 1}
+	}
+	h := New(nil, measurement.DefaultStrategy)
+	measured := make(map[int]bool)
+	for item := range h.measureBatch(context.Background(), items) {
+		require.NoError(t, item.err)
+		require.Equal(t, measurement.BFS, item.solution.Strategy)
+		measured[item.index] = true
+	}
+	require.Len(t, measured, len(items))
+	require.LessOrEqual(t, maxRunning, BatchWorkers)
+}
+
+func TestMeasureBatchCanceled(t *testing.T) {
+	mockBatchCache()
+	ctx, cancel := context.WithCancel(context.Background())
+	retrieveSolutionFromCache = func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+		cancel()
+		return nil, nil
+	}
+	items := make([]models.NewMeasurement, MaxBatchSize)
+	for i := range items {
+		items[i] = models.NewMeasurement{XCap: 3, YCap: 5, ZAmountWanted: 4}
+	}
+	h := New(nil, measurement.DefaultStrategy)
+	var measured int
+	for range h.measureBatch(ctx, items) {
+		measured++
+	}
+	require.Less(t, measured, len(items))
+}
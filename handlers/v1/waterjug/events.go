@@ -0,0 +1,146 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package waterjug
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/tiagomelo/golang-waterjug-api/measurement"
+	"github.com/tiagomelo/golang-waterjug-api/web"
+)
+
+// SSEContentType is the content type of Server-Sent Events responses.
+const SSEContentType = "text/event-stream"
+
+// HeartbeatInterval is the time between two heartbeat comments of an idle
+// event stream (15 seconds).
+const HeartbeatInterval = 15 * time.Second
+
+// Names of the events of a solution's event stream.
+const (
+	eventStep       = "step"
+	eventSolved     = "solved"
+	eventNoSolution = "no-solution"
+	eventError      = "error"
+)
+
+// For ease of unit testing.
+var (
+	heartbeatInterval = HeartbeatInterval
+	iterateSteps      = measurement.IterateSteps
+)
+
+// solvedEvent represents the data of the event ending a solved stream.
+type solvedEvent struct {
+	Steps    int    `json:"steps"`    // Steps represents the number of steps sent.
+	Strategy string `json:"strategy"` // Strategy represents the solving strategy that produced them.
+}
+
+// eventWriter writes Server-Sent Events, flushing each of them so that it
+// reaches the client right away. Once a write fails, the next ones are skipped.
+type eventWriter struct {
+	w   io.Writer
+	rc  *http.ResponseController
+	err error
+}
+
+// write writes the given chunk of the stream and flushes it.
+func (e *eventWriter) write(chunk string) error {
+	if e.err != nil {
+		return e.err
+	}
+	if _, e.err = io.WriteString(e.w, chunk); e.err == nil {
+		e.err = e.rc.Flush()
+	}
+	return e.err
+}
+
+// event writes the named event, with data encoded as JSON on a single line.
+// Like problems responded to on their own, characters such as & are left alone.
+func (e *eventWriter) event(name string, data any) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(data); err != nil {
+		return err
+	}
+	return e.write(fmt.Sprintf("event: %s\ndata: %s\n", name, buf.Bytes()))
+}
+
+// heartbeat writes a comment, which clients ignore, to keep idle
+// connections from being closed by proxies along the way.
+func (e *eventWriter) heartbeat() error {
+	return e.write(": heartbeat\n\n")
+}
+
+// Events is an HTTP handler streaming the solution to the measurement given
+// as query parameters as Server-Sent Events, for clients to animate it as it
+// comes out. Each step is sent as a "step" event, followed by a "solved" event
+// or, when there's no solution, a "no-solution" event carrying the problem;
+// any other failure ends the stream with an "error" event instead.
+// Heartbeat comments are sent while the solution is being searched for.
+// Solutions are always searched for breadth-first.
+func (h *handlers) Events(w http.ResponseWriter, r *http.Request) error {
+	newMeasurement, err := web.ParseMeasurementQuery(r)
+	if err != nil {
+		return web.BadRequest(err)
+	}
+	problem, err := h.prepare(newMeasurement)
+	if err != nil {
+		return err
+	}
+	type outcome struct {
+		steps *measurement.StepIterator
+		err   error
+	}
+	// The search can't be interrupted: when the client goes away, it's left
+	// to finish on its own, with room to leave its outcome.
+	outcomes := make(chan outcome, 1)
+	go func() {
+		steps, err := iterateSteps(problem)
+		outcomes <- outcome{steps: steps, err: err}
+	}()
+	w.Header().Set("Content-Type", SSEContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	events := &eventWriter{w: w, rc: http.NewResponseController(w)}
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	var result outcome
+	for searching := true; searching; {
+		select {
+		case result = <-outcomes:
+			searching = false
+		case <-ticker.C:
+			if events.heartbeat() != nil {
+				return nil
+			}
+		case <-r.Context().Done():
+			return nil
+		}
+	}
+	if result.err != nil {
+		name := eventError
+		var noSolution *measurement.NoSolutionError
+		if errors.As(result.err, &noSolution) {
+			name = eventNoSolution
+		}
+		events.event(name, web.ProblemFor(r, result.err))
+		return nil
+	}
+	for step, ok := result.steps.Next(); ok; step, ok = result.steps.Next() {
+		if err := events.event(eventStep, step); err != nil {
+			return nil // the client has gone away.
+		}
+	}
+	events.event(eventSolved, &solvedEvent{Steps: result.steps.Len(), Strategy: measurement.BFS})
+	return nil
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package waterjug
+
+import (
+	"bufio"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/measurement"
+	"github.com/tiagomelo/golang-waterjug-api/web"
+)
+
+func TestEvents(t *testing.T) {
+	testCases := []struct {
+		name               string
+		query              string
+		mockIterateSteps   func(p measurement.Problem) (*measurement.StepIterator, error)
+		expectedOutput     string
+		expectedStatusCode int
+	}{
+		{
+			name:  "happy path",
+			query: "x=3&y=5&z=4",
+			expectedOutput: "event: step\n" +
+				`data: {"step":1,"bucketX":0,"bucketY":5,"action":"Fill bucket Y"}` + "\n\n" +
+				"event: step\n" +
+				`data: {"step":2,"bucketX":3,"bucketY":2,"action":"Transfer from bucket Y to X"}` + "\n\n" +
+				"event: step\n" +
+				`data: {"step":3,"bucketX":0,"bucketY":2,"action":"Empty bucket X"}` + "\n\n" +
+				"event: step\n" +
+				`data: {"step":4,"bucketX":2,"bucketY":0,"action":"Transfer from bucket Y to X"}` + "\n\n" +
+				"event: step\n" +
+				`data: {"step":5,"bucketX":2,"bucketY":5,"action":"Fill bucket Y"}` + "\n\n" +
+				"event: step\n" +
+				`data: {"step":6,"bucketX":3,"bucketY":4,"action":"Transfer from bucket Y to X","status":"Solved"}` + "\n\n" +
+				"event: solved\n" +
+				`data: {"steps":6,"strategy":"bfs"}` + "\n\n",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:  "no solution",
+			query: "x=2&y=6&z=5",
+			expectedOutput: "event: no-solution\n" +
+				`data: {"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#no-solution","title":"No Solution","status":422,"detail":"no solution: 5 is not a multiple of gcd(2, 6) = 2","instance":"measure/stream?x=2&y=6&z=5","reason":"gcd_mismatch","x_capacity":2,"y_capacity":6,"z_amount_wanted":5,"gcd":2}` + "\n\n",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:  "error when searching",
+			query: "x=1&y=2&z=2",
+			mockIterateSteps: func(p measurement.Problem) (*measurement.StepIterator, error) {
+				return nil, errors.New("search error")
+			},
+			expectedOutput: "event: error\n" +
+				`data: {"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#internal-error","title":"Internal Server Error","status":500,"instance":"measure/stream?x=1&y=2&z=2"}` + "\n\n",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "invalid query parameter",
+			query:              "x=a&y=2&z=2",
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request","title":"Bad Request","status":400,"detail":"x must be an integer","instance":"measure/stream?x=a&y=2&z=2"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "validation error",
+			query:              "y=2&z=2",
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#validation-error","title":"Validation Failed","status":400,"detail":"the request has invalid fields","instance":"measure/stream?y=2&z=2","errors":[{"field":"x_capacity","error":"x_capacity is a required field"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+	originalIterateSteps := iterateSteps
+	defer func() {
+		iterateSteps = originalIterateSteps
+	}()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			iterateSteps = originalIterateSteps
+			if tc.mockIterateSteps != nil {
+				iterateSteps = tc.mockIterateSteps
+			}
+			req, err := http.NewRequest(http.MethodGet, "measure/stream?"+tc.query, nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			h := New(nil, measurement.DefaultStrategy)
+			handler := web.Adapt(testLog, h.Events)
+			handler.ServeHTTP(recorder, req)
+			require.Equal(t, tc.expectedStatusCode, recorder.Code)
+			if tc.expectedStatusCode == http.StatusOK {
+				require.Equal(t, SSEContentType, recorder.Header().Get("Content-Type"))
+				require.Equal(t, "no-cache", recorder.Header().Get("Cache-Control"))
+				require.Equal(t, tc.expectedOutput, recorder.Body.String())
+			} else {
+				require.JSONEq(t, tc.expectedOutput, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestEventsHeartbeat(t *testing.T) {
+	originalIterateSteps, originalHeartbeatInterval := iterateSteps, heartbeatInterval
+	defer func() {
+		iterateSteps, heartbeatInterval = originalIterateSteps, originalHeartbeatInterval
+	}()
+	heartbeatInterval = time.Millisecond
+	searched := make(chan struct{})
+	iterateSteps = func(p measurement.Problem) (*measurement.StepIterator, error) {
+		<-searched
+		return originalIterateSteps(p)
+	}
+	h := New(nil, measurement.DefaultStrategy)
+	server := httptest.NewServer(web.Adapt(testLog, h.Events))
+	defer server.Close()
+	resp, err := http.Get(server.URL + "?x=1&y=2&z=2")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	lines := bufio.NewScanner(resp.Body)
+	require.True(t, lines.Scan())
+	require.Equal(t, ": heartbeat", lines.Text())
+	close(searched)
+	var output []string
+	for lines.Scan() {
+		if lines.Text() != ": heartbeat" && lines.Text() != "" {
+			output = append(output, lines.Text())
+		}
+	}
+	require.NoError(t, lines.Err())
+	require.Equal(t, []string{
+		"event: step",
+		`data: {"step":1,"bucketX":0,"bucketY":2,"action":"Fill bucket Y","status":"Solved"}`,
+		"event: solved",
+		`data: {"steps":1,"strategy":"bfs"}`,
+	}, output)
+}
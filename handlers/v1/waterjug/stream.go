@@ -0,0 +1,102 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package waterjug
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+	"github.com/tiagomelo/golang-waterjug-api/web"
+)
+
+// NDJSONContentType is the content type of JSON Lines request and response
+// bodies.
+const NDJSONContentType = "application/x-ndjson"
+
+// MaxStreamLineSize is the maximum size of a line of a JSON Lines request
+// body (64 KiB).
+const MaxStreamLineSize = 64 * 1024
+
+// decodeStream reads the measurements of a JSON Lines body, one per line,
+// sending them as they're read. Blank lines are skipped. A line that can't
+// be decoded is sent as a bad request without stopping the others, but a
+// line too long or a failure to read the body ends the stream. The channel
+// is closed once the body is read or ctx is done.
+func decodeStream(ctx context.Context, body io.Reader) <-chan batchInput {
+	inputs := make(chan batchInput)
+	go func() {
+		defer close(inputs)
+		send := func(input batchInput) bool {
+			select {
+			case inputs <- input:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 0, 4096), MaxStreamLineSize)
+		var index int
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			input := batchInput{index: index}
+			index++
+			var newMeasurement models.NewMeasurement
+			if err := json.Unmarshal(line, &newMeasurement); err != nil {
+				input.err = web.BadRequest(err)
+			} else {
+				input.newMeasurement = &newMeasurement
+			}
+			if !send(input) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			send(batchInput{index: index, err: web.BadRequest(err)})
+		}
+	}()
+	return inputs
+}
+
+// Stream is an HTTP handler for measuring water jug solutions given as JSON
+// Lines, one measurement per line. It writes one result line per measurement
+// as soon as it's solved, so results come in no particular order, each with
+// its index: the position of the measurement in the body, blank lines aside.
+// Only a few measurements are read ahead of the ones being solved, so bodies
+// of any size are piped through with bounded memory.
+func (h *handlers) Stream(w http.ResponseWriter, r *http.Request) error {
+	defer r.Body.Close()
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	rc := http.NewResponseController(w)
+	// HTTP/1.x request bodies can't be read once the response is being
+	// written unless told otherwise. HTTP/2 ones always can, which is why
+	// its response writers don't support being told.
+	rc.EnableFullDuplex()
+	w.Header().Set("Content-Type", NDJSONContentType)
+	w.WriteHeader(http.StatusOK)
+	for item := range h.measureStream(ctx, decodeStream(ctx, r.Body)) {
+		line, err := json.Marshal(newBatchResult(r, item))
+		if err == nil {
+			_, err = w.Write(append(line, '\n'))
+		}
+		if err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			// The client has gone away; stop measuring for it.
+			cancel()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package waterjug
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/cache"
+	"github.com/tiagomelo/golang-waterjug-api/measurement"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+	"github.com/tiagomelo/golang-waterjug-api/web"
+)
+
+func TestStream(t *testing.T) {
+	testCases := []struct {
+		name                          string
+		input                         string
+		mockRetrieveSolutionFromCache func(ctx context.Context, cs cache.CacheService,
+			newMeasurement *models.NewMeasurement) (*models.Solution, error)
+		expectedOutput []string
+	}{
+		{
+			name: "happy path",
+			input: `{"x_capacity":1,"y_capacity":2,"z_amount_wanted":2}
+
+{"x_capacity":2,"y_capacity":6,"z_amount_wanted":5}
+{"x_capacity":1,
+{"y_capacity":2,"z_amount_wanted":1}`,
+			expectedOutput: []string{
+				`{"index":0,"solution":{"solution":[{"step":1,"bucketX":0,"bucketY":2,"action":"Fill bucket Y","status":"Solved"}],"strategy":"bfs"}}`,
+				`{"index":1,"error":{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#no-solution","title":"No Solution","status":422,"detail":"no solution: 5 is not a multiple of gcd(2, 6) = 2","instance":"measure/stream","reason":"gcd_mismatch","x_capacity":2,"y_capacity":6,"z_amount_wanted":5,"gcd":2}}`,
+				`{"index":2,"error":{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request","title":"Bad Request","status":400,"detail":"unexpected end of JSON input","instance":"measure/stream"}}`,
+				`{"index":3,"error":{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#validation-error","title":"Validation Failed","status":400,"detail":"the request has invalid fields","instance":"measure/stream","errors":[{"field":"x_capacity","error":"x_capacity is a required field"}]}}`,
+			},
+		},
+		{
+			name:  "error when retrieving solution from cache",
+			input: `{"x_capacity":1,"y_capacity":2,"z_amount_wanted":2}`,
+			mockRetrieveSolutionFromCache: func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+				return nil, &cache.Error{Err: errors.New("get error")}
+			},
+			expectedOutput: []string{
+				`{"index":0,"error":{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#service-unavailable","title":"Service Unavailable","status":503,"detail":"cache is unavailable","instance":"measure/stream"}}`,
+			},
+		},
+		{
+			name:  "line too long",
+			input: `{"x_capacity":1,"y_capacity":2,"z_amount_wanted":2}` + "\n" + strings.Repeat(" ", MaxStreamLineSize),
+			expectedOutput: []string{
+				`{"index":0,"solution":{"solution":[{"step":1,"bucketX":0,"bucketY":2,"action":"Fill bucket Y","status":"Solved"}],"strategy":"bfs"}}`,
+				`{"index":1,"error":{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request","title":"Bad Request","status":400,"detail":"bufio.Scanner: token too long","instance":"measure/stream"}}`,
+			},
+		},
+		{
+			name:  "empty body",
+			input: "",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockBatchCache()
+			if tc.mockRetrieveSolutionFromCache != nil {
+				retrieveSolutionFromCache = tc.mockRetrieveSolutionFromCache
+			}
+			req, err := http.NewRequest(http.MethodPost, "measure/stream", strings.NewReader(tc.input))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", NDJSONContentType)
+			recorder := httptest.NewRecorder()
+			h := New(nil, measurement.DefaultStrategy)
+			handler := web.Adapt(testLog, h.Stream)
+			handler.ServeHTTP(recorder, req)
+			require.Equal(t, http.StatusOK, recorder.Code)
+			require.Equal(t, NDJSONContentType, recorder.Header().Get("Content-Type"))
+			var output []string
+			for _, line := range strings.Split(recorder.Body.String(), "\n") {
+				if line != "" {
+					output = append(output, line)
+				}
+			}
+			require.ElementsMatch(t, tc.expectedOutput, output)
+		})
+	}
+}
+
+func TestStreamWritesResultsBeforeBodyEnds(t *testing.T) {
+	mockBatchCache()
+	h := New(nil, measurement.DefaultStrategy)
+	server := httptest.NewServer(web.Adapt(testLog, h.Stream))
+	defer server.Close()
+	body, bodyWriter := io.Pipe()
+	req, err := http.NewRequest(http.MethodPost, server.URL, body)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", NDJSONContentType)
+	responses := make(chan *http.Response)
+	go func() {
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			close(responses)
+			return
+		}
+		responses <- resp
+	}()
+	_, err = io.WriteString(bodyWriter, `{"x_capacity":1,"y_capacity":2,"z_amount_wanted":2}`+"\n")
+	require.NoError(t, err)
+	resp, ok := <-responses
+	require.True(t, ok)
+	defer resp.Body.Close()
+	results := bufio.NewScanner(resp.Body)
+	require.True(t, results.Scan())
+	require.Equal(t, `{"index":0,"solution":{"solution":[{"step":1,"bucketX":0,"bucketY":2,"action":"Fill bucket Y","status":"Solved"}],"strategy":"bfs"}}`, results.Text())
+	_, err = io.WriteString(bodyWriter, `{"x_capacity":1,"y_capacity":3,"z_amount_wanted":3}`+"\n")
+	require.NoError(t, err)
+	require.True(t, results.Scan())
+	require.Equal(t, `{"index":1,"solution":{"solution":[{"step":1,"bucketX":0,"bucketY":3,"action":"Fill bucket Y","status":"Solved"}],"strategy":"bfs"}}`, results.Text())
+	require.NoError(t, bodyWriter.Close())
+	require.False(t, results.Scan())
+	require.NoError(t, results.Err())
+}
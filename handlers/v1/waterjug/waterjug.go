@@ -5,28 +5,68 @@
 package waterjug
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
-	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/tiagomelo/golang-waterjug-api/cache"
 	"github.com/tiagomelo/golang-waterjug-api/measurement"
 	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+	"github.com/tiagomelo/golang-waterjug-api/render"
 	"github.com/tiagomelo/golang-waterjug-api/validate"
 	"github.com/tiagomelo/golang-waterjug-api/web"
 )
 
 // handlers represents HTTP handlers for the water jug measurement service.
 type handlers struct {
-	cache cache.CacheService
+	cache    cache.CacheService
+	strategy string
+}
+
+// Response formats for solutions.
+const (
+	formatJSON  = "json"
+	formatSVG   = "svg"
+	formatPNG   = "png"
+	formatASCII = "ascii"
+)
+
+// renderer renders a solution in a format other than JSON.
+type renderer struct {
+	contentType string
+	render      func(w io.Writer, solution *models.Solution, xCap, yCap int) error
+}
+
+// renderers maps response formats to their renderers.
+var renderers = map[string]renderer{
+	formatSVG:   {contentType: "image/svg+xml", render: render.SVG},
+	formatPNG:   {contentType: "image/png", render: render.PNG},
+	formatASCII: {contentType: "text/plain; charset=utf-8", render: render.ASCII},
+}
+
+// mediaTypes maps the media types accepted by clients to response formats.
+var mediaTypes = map[string]string{
+	"application/json": formatJSON,
+	"image/svg+xml":    formatSVG,
+	"image/png":        formatPNG,
+	"text/plain":       formatASCII,
 }
 
 // cache expiration time for cached solutions (24 hours).
 const CACHE_EXPIRATION_24H = 24 * time.Hour
 
+// SolutionCacheControl is the Cache-Control header of solutions measured by
+// GET requests, which never change for a given ETag (one year).
+const SolutionCacheControl = "public, max-age=31536000, immutable"
+
 // For ease of unit testing.
 var (
 	// jsonDecode decodes a JSON request body into a given struct.
@@ -44,44 +84,230 @@ var (
 		solution *models.Solution, expiration time.Duration) error {
 		return cache.StoreSolution(ctx, cs, measurement, solution, expiration)
 	}
+	// retrieveTreeFromCache retrieves the shortest-path tree of a jug pair from the cache.
+	retrieveTreeFromCache = func(ctx context.Context,
+		cs cache.CacheService, xCap, yCap int) (*measurement.Tree, error) {
+		return cache.RetrieveTree(ctx, cs, xCap, yCap)
+	}
+	// storeTreeInCache stores the shortest-path tree of a jug pair in the cache.
+	storeTreeInCache = func(ctx context.Context,
+		cs cache.CacheService, xCap, yCap int,
+		tree *measurement.Tree, expiration time.Duration) error {
+		return cache.StoreTree(ctx, cs, xCap, yCap, tree, expiration)
+	}
 )
 
-// New creates a new handlers instance with the provided cache service.
-func New(cache cache.CacheService) *handlers {
+// New creates a new handlers instance with the provided cache service
+// and the solving strategy used when a request does not specify one.
+func New(cache cache.CacheService, strategy string) *handlers {
 	return &handlers{
-		cache: cache,
+		cache:    cache,
+		strategy: strategy,
+	}
+}
+
+// responseFormat picks the response format from the "format" query parameter
+// or, when it's absent, from the first supported media type in the Accept header.
+func responseFormat(r *http.Request) (string, error) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		if _, ok := renderers[format]; !ok && format != formatJSON {
+			return "", fmt.Errorf(`unsupported format "%s"`, format)
+		}
+		return format, nil
+	}
+	for _, mediaRange := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType := strings.TrimSpace(strings.SplitN(mediaRange, ";", 2)[0])
+		if format, ok := mediaTypes[mediaType]; ok {
+			return format, nil
+		}
+	}
+	return formatJSON, nil
+}
+
+// debugMode reads the "debug" query parameter. A true value asks for
+// statistics about the search and "layers" also asks for the layer
+// in which breadth-first search discovered every state.
+func debugMode(r *http.Request) (debug bool, layers bool, err error) {
+	value := r.URL.Query().Get("debug")
+	switch value {
+	case "":
+		return false, false, nil
+	case "layers":
+		return true, true, nil
+	}
+	debug, err = strconv.ParseBool(value)
+	if err != nil {
+		return false, false, fmt.Errorf(`invalid debug mode "%s"`, value)
+	}
+	return debug, false, nil
+}
+
+// respondWithSolution responds with the solution rendered in the given format.
+// When etag is set, the response can be cached as identified by it, provided
+// the solution could be rendered.
+func respondWithSolution(w http.ResponseWriter, format string, solution *models.Solution, newMeasurement *models.NewMeasurement, etag string) error {
+	renderer, ok := renderers[format]
+	if !ok {
+		if etag != "" {
+			setCacheHeaders(w, etag)
+		}
+		web.RespondWithJson(w, http.StatusOK, solution)
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := renderer.render(&buf, solution, newMeasurement.XCap, newMeasurement.YCap); err != nil {
+		return err
+	}
+	if etag != "" {
+		setCacheHeaders(w, etag)
+	}
+	web.Respond(w, http.StatusOK, renderer.contentType, buf.Bytes())
+	return nil
+}
+
+// solve solves the given problem with the requested strategy. Breadth-first
+// search solutions are found by walking the jug pair's shortest-path tree,
+// which is cached, so that any later target for the same jugs is answered
+// without searching again. Trees don't cover accumulation mode nor action
+// priority orders.
+func (h *handlers) solve(ctx context.Context, newMeasurement *models.NewMeasurement, problem measurement.Problem) (*models.Solution, error) {
+	if newMeasurement.Strategy != measurement.BFS || problem.Accumulate || len(problem.ActionOrder) > 0 {
+		return measurement.MeasureWith(newMeasurement.Strategy, problem)
+	}
+	tree, err := retrieveTreeFromCache(ctx, h.cache, newMeasurement.XCap, newMeasurement.YCap)
+	if err != nil {
+		return nil, err
+	}
+	if tree == nil {
+		tree = measurement.BuildTree(newMeasurement.XCap, newMeasurement.YCap)
+		if err := storeTreeInCache(ctx, h.cache, newMeasurement.XCap, newMeasurement.YCap, tree, CACHE_EXPIRATION_24H); err != nil {
+			return nil, err
+		}
+	}
+	solution, err := tree.Solve(problem)
+	if solution != nil {
+		solution.Strategy = measurement.BFS
 	}
+	return solution, err
 }
 
 // Measure is an HTTP handler for measuring water jug solutions.
-func (h *handlers) Measure(w http.ResponseWriter, r *http.Request) {
+// The solution is rendered as JSON, an animated SVG, a PNG contact sheet
+// or ASCII art, according to the "format" query parameter or the Accept header.
+// With the "debug" query parameter, the solution is searched for again instead
+// of being taken from the cache, and JSON responses carry search diagnostics.
+func (h *handlers) Measure(w http.ResponseWriter, r *http.Request) error {
 	defer r.Body.Close()
+	format, err := responseFormat(r)
+	if err != nil {
+		return web.BadRequest(err)
+	}
+	debug, layers, err := debugMode(r)
+	if err != nil {
+		return web.BadRequest(err)
+	}
 	var newMeasurement models.NewMeasurement
 	if err := jsonDecode(r.Body, &newMeasurement); err != nil {
-		web.RespondWithError(w, http.StatusBadRequest, err.Error())
-		return
+		return web.BadRequest(err)
+	}
+	problem, err := h.prepare(&newMeasurement)
+	if err != nil {
+		return err
+	}
+	if debug {
+		solution, err := measurement.Diagnose(newMeasurement.Strategy, problem, layers)
+		if err != nil {
+			return err
+		}
+		return respondWithSolution(w, format, solution, &newMeasurement, "")
+	}
+	solution, err := h.measure(r.Context(), &newMeasurement, problem)
+	if err != nil {
+		return err
 	}
+	return respondWithSolution(w, format, solution, &newMeasurement, "")
+}
+
+// prepare validates the measurement and turns it into the problem to solve.
+// Measurements without strategy get the default one.
+func (h *handlers) prepare(newMeasurement *models.NewMeasurement) (measurement.Problem, error) {
 	if err := validate.Check(newMeasurement); err != nil {
-		web.RespondWithError(w, http.StatusBadRequest, err.Error())
-		return
+		return measurement.Problem{}, err
+	}
+	problem, err := measurement.NewProblem(newMeasurement)
+	if err != nil {
+		return measurement.Problem{}, err
+	}
+	if newMeasurement.Strategy == "" {
+		newMeasurement.Strategy = h.strategy
 	}
-	cachedSolution, err := retrieveSolutionFromCache(r.Context(), h.cache, &newMeasurement)
+	return problem, nil
+}
+
+// measure returns the solution to the given problem, from the cache when possible.
+func (h *handlers) measure(ctx context.Context, newMeasurement *models.NewMeasurement, problem measurement.Problem) (*models.Solution, error) {
+	cachedSolution, err := retrieveSolutionFromCache(ctx, h.cache, newMeasurement)
 	if err != nil {
-		web.RespondWithError(w, http.StatusInternalServerError, err.Error())
-		return
+		return nil, err
 	}
 	if cachedSolution != nil {
-		web.RespondWithJson(w, http.StatusOK, cachedSolution)
-		return
+		return cachedSolution, nil
 	}
-	solution := measurement.Measure(newMeasurement.XCap, newMeasurement.YCap, newMeasurement.ZAmountWanted)
-	if solution == nil {
-		web.RespondWithError(w, http.StatusBadRequest, errors.New("no solution").Error())
-		return
+	solution, err := h.solve(ctx, newMeasurement, problem)
+	if err != nil {
+		return nil, err
+	}
+	if err := storeSolutionInCache(ctx, h.cache, newMeasurement, solution, CACHE_EXPIRATION_24H); err != nil {
+		return nil, err
+	}
+	return solution, nil
+}
+
+// solutionETag derives a strong entity tag from everything the response
+// depends on: the solver version, the strategy, the capacities, the desired
+// amount, the format and the content coding, which the compression middleware
+// has already picked.
+func solutionETag(w http.ResponseWriter, newMeasurement *models.NewMeasurement, format string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s#%s#%d#%d#%d#%s#%s", measurement.SolverVersion, newMeasurement.Strategy,
+		newMeasurement.XCap, newMeasurement.YCap, newMeasurement.ZAmountWanted, format, w.Header().Get("Content-Encoding"))))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// setCacheHeaders lets clients and shared caches keep the solution
+// identified by etag for as long as they want.
+func setCacheHeaders(w http.ResponseWriter, etag string) {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", SolutionCacheControl)
+	w.Header().Add("Vary", "Accept")
+}
+
+// MeasureQuery is an HTTP handler for measuring water jug solutions given
+// as query parameters, so that responses can be cached by browsers and CDNs.
+// A solution only depends on its inputs and the solver version, from which
+// its strong ETag is derived; requests whose If-None-Match matches it are
+// answered with 304 Not Modified without solving again.
+func (h *handlers) MeasureQuery(w http.ResponseWriter, r *http.Request) error {
+	format, err := responseFormat(r)
+	if err != nil {
+		return web.BadRequest(err)
+	}
+	newMeasurement, err := web.ParseMeasurementQuery(r)
+	if err != nil {
+		return web.BadRequest(err)
 	}
-	if err := storeSolutionInCache(r.Context(), h.cache, &newMeasurement, solution, CACHE_EXPIRATION_24H); err != nil {
-		web.RespondWithError(w, http.StatusInternalServerError, err.Error())
-		return
+	problem, err := h.prepare(newMeasurement)
+	if err != nil {
+		return err
+	}
+	etag := solutionETag(w, newMeasurement, format)
+	if web.ETagMatches(r, etag) {
+		setCacheHeaders(w, etag)
+		web.RespondWithStatus(w, http.StatusNotModified)
+		return nil
+	}
+	solution, err := h.measure(r.Context(), newMeasurement, problem)
+	if err != nil {
+		return err
 	}
-	web.RespondWithJson(w, http.StatusOK, solution)
+	return respondWithSolution(w, format, solution, newMeasurement, etag)
 }
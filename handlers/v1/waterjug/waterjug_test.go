@@ -7,18 +7,27 @@ package waterjug
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/tiagomelo/golang-waterjug-api/cache"
+	"github.com/tiagomelo/golang-waterjug-api/measurement"
 	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+	"github.com/tiagomelo/golang-waterjug-api/render"
+	"github.com/tiagomelo/golang-waterjug-api/web"
 )
 
+// testLog discards the logs of failed requests.
+var testLog = slog.New(slog.NewTextHandler(io.Discard, nil))
+
 func TestMeasure(t *testing.T) {
 	testCases := []struct {
 		name                          string
@@ -29,6 +38,10 @@ func TestMeasure(t *testing.T) {
 		mockStoreSolutionInCache func(ctx context.Context, cs cache.CacheService,
 			measurement *models.NewMeasurement, solution *models.Solution,
 			expiration time.Duration) error
+		mockRetrieveTreeFromCache func(ctx context.Context, cs cache.CacheService,
+			xCap, yCap int) (*measurement.Tree, error)
+		mockStoreTreeInCache func(ctx context.Context, cs cache.CacheService,
+			xCap, yCap int, tree *measurement.Tree, expiration time.Duration) error
 		expectedOutput     string
 		expectedStatusCode int
 	}{
@@ -41,9 +54,123 @@ func TestMeasure(t *testing.T) {
 			mockStoreSolutionInCache: func(ctx context.Context, cs cache.CacheService, measurement *models.NewMeasurement, solution *models.Solution, expiration time.Duration) error {
 				return nil
 			},
-			expectedOutput:     "{\"solution\":[{\"step\":1,\"bucketX\":0,\"bucketY\":100,\"action\":\"Fill bucket Y\"},{\"step\":2,\"bucketX\":2,\"bucketY\":98,\"action\":\"Transfer from bucket Y to X\"},{\"step\":3,\"bucketX\":0,\"bucketY\":98,\"action\":\"Empty bucket X\"},{\"step\":4,\"bucketX\":2,\"bucketY\":96,\"action\":\"Transfer from bucket Y to X\",\"status\":\"Solved\"}]}",
+			expectedOutput:     "{\"solution\":[{\"step\":1,\"bucketX\":0,\"bucketY\":100,\"action\":\"Fill bucket Y\"},{\"step\":2,\"bucketX\":2,\"bucketY\":98,\"action\":\"Transfer from bucket Y to X\"},{\"step\":3,\"bucketX\":0,\"bucketY\":98,\"action\":\"Empty bucket X\"},{\"step\":4,\"bucketX\":2,\"bucketY\":96,\"action\":\"Transfer from bucket Y to X\",\"status\":\"Solved\"}],\"strategy\":\"bfs\"}",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:  "happy path, strategy informed",
+			input: `{"x_capacity":2,"y_capacity":100,"z_amount_wanted":96,"strategy":"math"}`,
+			mockRetrieveSolutionFromCache: func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+				return nil, nil
+			},
+			mockStoreSolutionInCache: func(ctx context.Context, cs cache.CacheService, measurement *models.NewMeasurement, solution *models.Solution, expiration time.Duration) error {
+				return nil
+			},
+			expectedOutput:     "{\"solution\":[{\"step\":1,\"bucketX\":0,\"bucketY\":100,\"action\":\"Fill bucket Y\"},{\"step\":2,\"bucketX\":2,\"bucketY\":98,\"action\":\"Transfer from bucket Y to X\"},{\"step\":3,\"bucketX\":0,\"bucketY\":98,\"action\":\"Empty bucket X\"},{\"step\":4,\"bucketX\":2,\"bucketY\":96,\"action\":\"Transfer from bucket Y to X\",\"status\":\"Solved\"}],\"strategy\":\"math\"}",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:  "happy path, custom goal",
+			input: `{"x_capacity":5,"y_capacity":3,"goal":{"any_of":[2,7]}}`,
+			mockRetrieveSolutionFromCache: func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+				return nil, nil
+			},
+			mockStoreSolutionInCache: func(ctx context.Context, cs cache.CacheService, measurement *models.NewMeasurement, solution *models.Solution, expiration time.Duration) error {
+				return nil
+			},
+			expectedOutput:     "{\"solution\":[{\"step\":1,\"bucketX\":5,\"bucketY\":0,\"action\":\"Fill bucket X\"},{\"step\":2,\"bucketX\":2,\"bucketY\":3,\"action\":\"Transfer from bucket X to Y\",\"status\":\"Solved\"}],\"strategy\":\"bfs\"}",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:  "happy path, accumulation",
+			input: `{"x_capacity":3,"y_capacity":5,"z_amount_wanted":8,"accumulate":true}`,
+			mockRetrieveSolutionFromCache: func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+				return nil, nil
+			},
+			mockStoreSolutionInCache: func(ctx context.Context, cs cache.CacheService, measurement *models.NewMeasurement, solution *models.Solution, expiration time.Duration) error {
+				return nil
+			},
+			expectedOutput:     "{\"solution\":[{\"step\":1,\"bucketX\":3,\"bucketY\":0,\"receiver\":0,\"action\":\"Fill bucket X\"},{\"step\":2,\"bucketX\":3,\"bucketY\":5,\"receiver\":0,\"action\":\"Fill bucket Y\"},{\"step\":3,\"bucketX\":0,\"bucketY\":5,\"receiver\":3,\"action\":\"Pour bucket X into receiver\"},{\"step\":4,\"bucketX\":0,\"bucketY\":0,\"receiver\":8,\"action\":\"Pour bucket Y into receiver\",\"status\":\"Solved\"}],\"strategy\":\"bfs\"}",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "accumulation with custom goal",
+			input:              `{"x_capacity":3,"y_capacity":5,"accumulate":true,"goal":{"x":1}}`,
+			expectedOutput:     "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#validation-error\",\"title\":\"Validation Failed\",\"status\":400,\"detail\":\"the request has invalid fields\",\"instance\":\"measure\",\"errors\":[{\"field\":\"goal\",\"error\":\"goal cannot be used together with accumulate\"}]}",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "happy path, solution fits maximum number of steps",
+			input: `{"x_capacity":2,"y_capacity":100,"z_amount_wanted":96,"max_steps":4}`,
+			mockRetrieveSolutionFromCache: func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+				return nil, nil
+			},
+			mockStoreSolutionInCache: func(ctx context.Context, cs cache.CacheService, measurement *models.NewMeasurement, solution *models.Solution, expiration time.Duration) error {
+				return nil
+			},
+			expectedOutput:     "{\"solution\":[{\"step\":1,\"bucketX\":0,\"bucketY\":100,\"action\":\"Fill bucket Y\"},{\"step\":2,\"bucketX\":2,\"bucketY\":98,\"action\":\"Transfer from bucket Y to X\"},{\"step\":3,\"bucketX\":0,\"bucketY\":98,\"action\":\"Empty bucket X\"},{\"step\":4,\"bucketX\":2,\"bucketY\":96,\"action\":\"Transfer from bucket Y to X\",\"status\":\"Solved\"}],\"strategy\":\"bfs\",\"stepLimit\":{\"maxSteps\":4,\"fits\":true,\"optimum\":4}}",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:  "no solution within maximum number of steps",
+			input: `{"x_capacity":2,"y_capacity":100,"z_amount_wanted":96,"max_steps":3}`,
+			mockRetrieveSolutionFromCache: func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+				return nil, nil
+			},
+			mockStoreSolutionInCache: func(ctx context.Context, cs cache.CacheService, measurement *models.NewMeasurement, solution *models.Solution, expiration time.Duration) error {
+				return nil
+			},
+			expectedOutput:     "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#no-solution\",\"title\":\"No Solution\",\"status\":422,\"detail\":\"no solution within 3 steps: the optimal solution takes 4\",\"instance\":\"measure\",\"reason\":\"budget_exhausted\",\"x_capacity\":2,\"y_capacity\":100,\"z_amount_wanted\":96,\"maxSteps\":3,\"optimum\":4}",
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:  "happy path, action order",
+			input: `{"x_capacity":1,"y_capacity":4,"z_amount_wanted":2,"action_order":["Fill bucket Y","Transfer from bucket Y to X"]}`,
+			mockRetrieveSolutionFromCache: func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+				return nil, nil
+			},
+			mockStoreSolutionInCache: func(ctx context.Context, cs cache.CacheService, measurement *models.NewMeasurement, solution *models.Solution, expiration time.Duration) error {
+				return nil
+			},
+			expectedOutput:     "{\"solution\":[{\"step\":1,\"bucketX\":0,\"bucketY\":4,\"action\":\"Fill bucket Y\"},{\"step\":2,\"bucketX\":1,\"bucketY\":3,\"action\":\"Transfer from bucket Y to X\"},{\"step\":3,\"bucketX\":0,\"bucketY\":3,\"action\":\"Empty bucket X\"},{\"step\":4,\"bucketX\":1,\"bucketY\":2,\"action\":\"Transfer from bucket Y to X\",\"status\":\"Solved\"}],\"strategy\":\"bfs\"}",
 			expectedStatusCode: http.StatusOK,
 		},
+		{
+			name:               "unknown action in action order",
+			input:              `{"x_capacity":2,"y_capacity":4,"z_amount_wanted":2,"action_order":["Drink bucket X"]}`,
+			expectedOutput:     "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#validation-error\",\"title\":\"Validation Failed\",\"status\":400,\"detail\":\"the request has invalid fields\",\"instance\":\"measure\",\"errors\":[{\"field\":\"action_order[0]\",\"error\":\"action_order[0] must be one of: Fill bucket X, Fill bucket Y, Empty bucket X, Empty bucket Y, Transfer from bucket X to Y, Transfer from bucket Y to X, Pour bucket X into receiver, Pour bucket Y into receiver\"}]}",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "repeated action in action order",
+			input:              `{"x_capacity":2,"y_capacity":4,"z_amount_wanted":2,"action_order":["Fill bucket Y","Fill bucket Y"]}`,
+			expectedOutput:     "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#validation-error\",\"title\":\"Validation Failed\",\"status\":400,\"detail\":\"the request has invalid fields\",\"instance\":\"measure\",\"errors\":[{\"field\":\"action_order\",\"error\":\"action_order must contain unique values\"}]}",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "action order not supported by strategy",
+			input: `{"x_capacity":2,"y_capacity":4,"z_amount_wanted":2,"action_order":["Fill bucket Y"],"strategy":"astar"}`,
+			mockRetrieveSolutionFromCache: func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+				return nil, nil
+			},
+			expectedOutput:     "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"strategy does not support action priority orders\",\"instance\":\"measure\"}",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "empty goal",
+			input:              `{"x_capacity":5,"y_capacity":3,"goal":{}}`,
+			expectedOutput:     "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"goal must specify at least one condition\",\"instance\":\"measure\"}",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "custom goal not supported by strategy",
+			input: `{"x_capacity":5,"y_capacity":3,"goal":{"x":4},"strategy":"math"}`,
+			mockRetrieveSolutionFromCache: func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+				return nil, nil
+			},
+			expectedOutput:     "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"strategy does not support custom goals\",\"instance\":\"measure\"}",
+			expectedStatusCode: http.StatusBadRequest,
+		},
 		{
 			name:  "happy path, solution stored in cache",
 			input: `{"x_capacity":2,"y_capacity":100,"z_amount_wanted":96}`,
@@ -72,17 +199,23 @@ func TestMeasure(t *testing.T) {
 			mockJsonDecode: func(r io.Reader, v any) error {
 				return errors.New("decode error")
 			},
-			expectedOutput:     "{\"error\":\"decode error\"}",
+			expectedOutput:     "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request\",\"title\":\"Bad Request\",\"status\":400,\"detail\":\"decode error\",\"instance\":\"measure\"}",
 			expectedStatusCode: http.StatusBadRequest,
 		},
 		{
 			name:               "input validation error",
 			input:              `{}`,
-			expectedOutput:     "{\"error\":\"[{\\\"field\\\":\\\"x_capacity\\\",\\\"error\\\":\\\"x_capacity is a required field\\\"},{\\\"field\\\":\\\"y_capacity\\\",\\\"error\\\":\\\"y_capacity is a required field\\\"},{\\\"field\\\":\\\"z_amount_wanted\\\",\\\"error\\\":\\\"z_amount_wanted is a required field\\\"}]\"}",
+			expectedOutput:     "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#validation-error\",\"title\":\"Validation Failed\",\"status\":400,\"detail\":\"the request has invalid fields\",\"instance\":\"measure\",\"errors\":[{\"field\":\"x_capacity\",\"error\":\"x_capacity is a required field\"},{\"field\":\"y_capacity\",\"error\":\"y_capacity is a required field\"},{\"field\":\"z_amount_wanted\",\"error\":\"z_amount_wanted is a required field\"}]}",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "unknown strategy",
+			input:              `{"x_capacity":2,"y_capacity":100,"z_amount_wanted":96,"strategy":"dfs"}`,
+			expectedOutput:     "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#validation-error\",\"title\":\"Validation Failed\",\"status\":400,\"detail\":\"the request has invalid fields\",\"instance\":\"measure\",\"errors\":[{\"field\":\"strategy\",\"error\":\"strategy must be one of: astar, bfs, bidirectional, math\"}]}",
 			expectedStatusCode: http.StatusBadRequest,
 		},
 		{
-			name:  "no solution",
+			name:  "no solution, gcd mismatch",
 			input: `{"x_capacity":2,"y_capacity":6,"z_amount_wanted":5}`,
 			mockRetrieveSolutionFromCache: func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
 				return nil, nil
@@ -90,17 +223,68 @@ func TestMeasure(t *testing.T) {
 			mockStoreSolutionInCache: func(ctx context.Context, cs cache.CacheService, measurement *models.NewMeasurement, solution *models.Solution, expiration time.Duration) error {
 				return nil
 			},
-			expectedOutput:     "{\"error\":\"no solution\"}",
-			expectedStatusCode: http.StatusBadRequest,
+			expectedOutput:     "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#no-solution\",\"title\":\"No Solution\",\"status\":422,\"detail\":\"no solution: 5 is not a multiple of gcd(2, 6) = 2\",\"instance\":\"measure\",\"reason\":\"gcd_mismatch\",\"x_capacity\":2,\"y_capacity\":6,\"z_amount_wanted\":5,\"gcd\":2}",
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:  "no solution, constraint violation",
+			input: `{"x_capacity":5,"y_capacity":3,"goal":{"x":1,"y":1}}`,
+			mockRetrieveSolutionFromCache: func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+				return nil, nil
+			},
+			expectedOutput:     "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#no-solution\",\"title\":\"No Solution\",\"status\":422,\"detail\":\"no solution: no reachable state satisfies the goal\",\"instance\":\"measure\",\"reason\":\"constraint_violation\",\"x_capacity\":5,\"y_capacity\":3}",
+			expectedStatusCode: http.StatusUnprocessableEntity,
 		},
 		{
 			name:  "error when retrieving solution from cache",
 			input: `{"x_capacity":2,"y_capacity":100,"z_amount_wanted":96}`,
 			mockRetrieveSolutionFromCache: func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
-				return nil, errors.New("get error")
+				return nil, &cache.Error{Err: errors.New("get error")}
+			},
+			expectedOutput:     "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#service-unavailable\",\"title\":\"Service Unavailable\",\"status\":503,\"detail\":\"cache is unavailable\",\"instance\":\"measure\"}",
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:  "happy path, tree stored in cache",
+			input: `{"x_capacity":2,"y_capacity":100,"z_amount_wanted":96}`,
+			mockRetrieveSolutionFromCache: func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+				return nil, nil
+			},
+			mockStoreSolutionInCache: func(ctx context.Context, cs cache.CacheService, measurement *models.NewMeasurement, solution *models.Solution, expiration time.Duration) error {
+				return nil
+			},
+			mockRetrieveTreeFromCache: func(ctx context.Context, cs cache.CacheService, xCap, yCap int) (*measurement.Tree, error) {
+				return measurement.BuildTree(xCap, yCap), nil
+			},
+			mockStoreTreeInCache: func(ctx context.Context, cs cache.CacheService, xCap, yCap int, tree *measurement.Tree, expiration time.Duration) error {
+				return errors.New("tree should not be stored again")
+			},
+			expectedOutput:     "{\"solution\":[{\"step\":1,\"bucketX\":0,\"bucketY\":100,\"action\":\"Fill bucket Y\"},{\"step\":2,\"bucketX\":2,\"bucketY\":98,\"action\":\"Transfer from bucket Y to X\"},{\"step\":3,\"bucketX\":0,\"bucketY\":98,\"action\":\"Empty bucket X\"},{\"step\":4,\"bucketX\":2,\"bucketY\":96,\"action\":\"Transfer from bucket Y to X\",\"status\":\"Solved\"}],\"strategy\":\"bfs\"}",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:  "error when retrieving tree from cache",
+			input: `{"x_capacity":2,"y_capacity":100,"z_amount_wanted":96}`,
+			mockRetrieveSolutionFromCache: func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+				return nil, nil
+			},
+			mockRetrieveTreeFromCache: func(ctx context.Context, cs cache.CacheService, xCap, yCap int) (*measurement.Tree, error) {
+				return nil, &cache.Error{Err: errors.New("get tree error")}
+			},
+			expectedOutput:     "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#service-unavailable\",\"title\":\"Service Unavailable\",\"status\":503,\"detail\":\"cache is unavailable\",\"instance\":\"measure\"}",
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:  "error when storing tree in cache",
+			input: `{"x_capacity":2,"y_capacity":100,"z_amount_wanted":96}`,
+			mockRetrieveSolutionFromCache: func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+				return nil, nil
+			},
+			mockStoreTreeInCache: func(ctx context.Context, cs cache.CacheService, xCap, yCap int, tree *measurement.Tree, expiration time.Duration) error {
+				return &cache.Error{Err: errors.New("set tree error")}
 			},
-			expectedOutput:     "{\"error\":\"get error\"}",
-			expectedStatusCode: http.StatusInternalServerError,
+			expectedOutput:     "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#service-unavailable\",\"title\":\"Service Unavailable\",\"status\":503,\"detail\":\"cache is unavailable\",\"instance\":\"measure\"}",
+			expectedStatusCode: http.StatusServiceUnavailable,
 		},
 		{
 			name:  "error when storing solution in cache",
@@ -109,10 +293,10 @@ func TestMeasure(t *testing.T) {
 				return nil, nil
 			},
 			mockStoreSolutionInCache: func(ctx context.Context, cs cache.CacheService, measurement *models.NewMeasurement, solution *models.Solution, expiration time.Duration) error {
-				return errors.New("set error")
+				return &cache.Error{Err: errors.New("set error")}
 			},
-			expectedOutput:     "{\"error\":\"set error\"}",
-			expectedStatusCode: http.StatusInternalServerError,
+			expectedOutput:     "{\"type\":\"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#service-unavailable\",\"title\":\"Service Unavailable\",\"status\":503,\"detail\":\"cache is unavailable\",\"instance\":\"measure\"}",
+			expectedStatusCode: http.StatusServiceUnavailable,
 		},
 	}
 	originalJsonDecode := jsonDecode
@@ -126,15 +310,321 @@ func TestMeasure(t *testing.T) {
 			}
 			retrieveSolutionFromCache = tc.mockRetrieveSolutionFromCache
 			storeSolutionInCache = tc.mockStoreSolutionInCache
+			retrieveTreeFromCache = tc.mockRetrieveTreeFromCache
+			if retrieveTreeFromCache == nil {
+				retrieveTreeFromCache = func(ctx context.Context, cs cache.CacheService, xCap, yCap int) (*measurement.Tree, error) {
+					return nil, nil
+				}
+			}
+			storeTreeInCache = tc.mockStoreTreeInCache
+			if storeTreeInCache == nil {
+				storeTreeInCache = func(ctx context.Context, cs cache.CacheService, xCap, yCap int, tree *measurement.Tree, expiration time.Duration) error {
+					return nil
+				}
+			}
 			req, err := http.NewRequest(http.MethodPost, "measure", bytes.NewBuffer([]byte(tc.input)))
 			req.Header.Set("Content-Type", "application/json")
 			require.NoError(t, err)
 			recorder := httptest.NewRecorder()
-			h := New(nil)
-			handler := http.HandlerFunc((h).Measure)
+			h := New(nil, measurement.DefaultStrategy)
+			handler := web.Adapt(testLog, h.Measure)
 			handler.ServeHTTP(recorder, req)
 			require.Equal(t, tc.expectedStatusCode, recorder.Code)
 			require.Equal(t, tc.expectedOutput, recorder.Body.String())
 		})
 	}
 }
+
+func TestMeasureFormats(t *testing.T) {
+	testCases := []struct {
+		name                string
+		query               string
+		accept              string
+		expectedContentType string
+		expectedPrefix      string
+		expectedStatusCode  int
+	}{
+		{
+			name:                "json by default",
+			expectedContentType: "application/json",
+			expectedPrefix:      `{"solution":[{"step":1,"bucketX":0,"bucketY":2,"action":"Fill bucket Y","status":"Solved"}]`,
+			expectedStatusCode:  http.StatusOK,
+		},
+		{
+			name:                "ascii by query parameter",
+			query:               "?format=ascii",
+			accept:              "image/png",
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedPrefix:      "Start\n",
+			expectedStatusCode:  http.StatusOK,
+		},
+		{
+			name:                "svg by content negotiation",
+			accept:              "text/html, image/svg+xml;q=0.9, */*;q=0.8",
+			expectedContentType: "image/svg+xml",
+			expectedPrefix:      `<svg xmlns="http://www.w3.org/2000/svg"`,
+			expectedStatusCode:  http.StatusOK,
+		},
+		{
+			name:                "png by content negotiation",
+			accept:              "image/png",
+			expectedContentType: "image/png",
+			expectedPrefix:      "\x89PNG",
+			expectedStatusCode:  http.StatusOK,
+		},
+		{
+			name:                "unsupported format",
+			query:               "?format=gif",
+			expectedContentType: "application/problem+json",
+			expectedPrefix:      `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request","title":"Bad Request","status":400,"detail":"unsupported format \"gif\"","instance":"measure?format=gif"}`,
+			expectedStatusCode:  http.StatusBadRequest,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			retrieveSolutionFromCache = func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+				return nil, nil
+			}
+			storeSolutionInCache = func(ctx context.Context, cs cache.CacheService, measurement *models.NewMeasurement, solution *models.Solution, expiration time.Duration) error {
+				return nil
+			}
+			retrieveTreeFromCache = func(ctx context.Context, cs cache.CacheService, xCap, yCap int) (*measurement.Tree, error) {
+				return nil, nil
+			}
+			storeTreeInCache = func(ctx context.Context, cs cache.CacheService, xCap, yCap int, tree *measurement.Tree, expiration time.Duration) error {
+				return nil
+			}
+			input := `{"x_capacity":1,"y_capacity":2,"z_amount_wanted":2}`
+			req, err := http.NewRequest(http.MethodPost, "measure"+tc.query, bytes.NewBuffer([]byte(input)))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			recorder := httptest.NewRecorder()
+			h := New(nil, measurement.DefaultStrategy)
+			handler := web.Adapt(testLog, h.Measure)
+			handler.ServeHTTP(recorder, req)
+			require.Equal(t, tc.expectedStatusCode, recorder.Code)
+			require.Equal(t, tc.expectedContentType, recorder.Header().Get("Content-Type"))
+			require.True(t, strings.HasPrefix(recorder.Body.String(), tc.expectedPrefix), recorder.Body.String())
+		})
+	}
+}
+
+func TestMeasureDebug(t *testing.T) {
+	testCases := []struct {
+		name               string
+		query              string
+		input              string
+		expectedStrategy   string
+		expectedLayers     bool
+		expectedOutput     string
+		expectedStatusCode int
+	}{
+		{
+			name:               "search statistics",
+			query:              "?debug=true",
+			input:              `{"x_capacity":3,"y_capacity":5,"z_amount_wanted":4}`,
+			expectedStrategy:   "bfs",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "search statistics with layers",
+			query:              "?debug=layers",
+			input:              `{"x_capacity":3,"y_capacity":5,"z_amount_wanted":4}`,
+			expectedStrategy:   "bfs",
+			expectedLayers:     true,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "search statistics with strategy informed",
+			query:              "?debug=1",
+			input:              `{"x_capacity":3,"y_capacity":5,"z_amount_wanted":4,"strategy":"astar"}`,
+			expectedStrategy:   "astar",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "invalid debug mode",
+			query:              "?debug=verbose",
+			input:              `{"x_capacity":3,"y_capacity":5,"z_amount_wanted":4}`,
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request","title":"Bad Request","status":400,"detail":"invalid debug mode \"verbose\"","instance":"measure?debug=verbose"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "no solution",
+			query:              "?debug=true",
+			input:              `{"x_capacity":2,"y_capacity":4,"z_amount_wanted":3}`,
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#no-solution","title":"No Solution","status":422,"detail":"no solution: 3 is not a multiple of gcd(2, 4) = 2","instance":"measure?debug=true","reason":"gcd_mismatch","x_capacity":2,"y_capacity":4,"z_amount_wanted":3,"gcd":2}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			retrieveSolutionFromCache = func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+				t.Fatal("debug responses must not come from the cache")
+				return nil, nil
+			}
+			storeSolutionInCache = func(ctx context.Context, cs cache.CacheService, measurement *models.NewMeasurement, solution *models.Solution, expiration time.Duration) error {
+				t.Fatal("debug responses must not be cached")
+				return nil
+			}
+			req, err := http.NewRequest(http.MethodPost, "measure"+tc.query, bytes.NewBuffer([]byte(tc.input)))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+			h := New(nil, measurement.DefaultStrategy)
+			handler := web.Adapt(testLog, h.Measure)
+			handler.ServeHTTP(recorder, req)
+			require.Equal(t, tc.expectedStatusCode, recorder.Code)
+			if tc.expectedOutput != "" {
+				require.Equal(t, tc.expectedOutput, recorder.Body.String())
+				return
+			}
+			var solution models.Solution
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &solution))
+			require.Len(t, solution.Steps, 6)
+			require.NotNil(t, solution.Diagnostics)
+			require.Equal(t, tc.expectedStrategy, solution.Diagnostics.Strategy)
+			require.Positive(t, solution.Diagnostics.Expanded)
+			require.Positive(t, solution.Diagnostics.MaxFrontier)
+			require.Positive(t, solution.Diagnostics.Visited)
+			require.NotEmpty(t, solution.Diagnostics.SolverTime)
+			require.Equal(t, tc.expectedLayers, solution.Diagnostics.Layers != nil)
+		})
+	}
+}
+
+func TestMeasureQuery(t *testing.T) {
+	const etag = `"54796ee759c333a10d83757bb9c94057"`
+	testCases := []struct {
+		name                          string
+		query                         string
+		ifNoneMatch                   string
+		mockRetrieveSolutionFromCache func(ctx context.Context, cs cache.CacheService,
+			newMeasurement *models.NewMeasurement) (*models.Solution, error)
+		expectedOutput       string
+		expectedStatusCode   int
+		expectedETag         string
+		expectedCacheControl string
+	}{
+		{
+			name:                 "happy path",
+			query:                "?x=2&y=100&z=96",
+			expectedOutput:       "{\"solution\":[{\"step\":1,\"bucketX\":0,\"bucketY\":100,\"action\":\"Fill bucket Y\"},{\"step\":2,\"bucketX\":2,\"bucketY\":98,\"action\":\"Transfer from bucket Y to X\"},{\"step\":3,\"bucketX\":0,\"bucketY\":98,\"action\":\"Empty bucket X\"},{\"step\":4,\"bucketX\":2,\"bucketY\":96,\"action\":\"Transfer from bucket Y to X\",\"status\":\"Solved\"}],\"strategy\":\"bfs\"}",
+			expectedStatusCode:   http.StatusOK,
+			expectedETag:         etag,
+			expectedCacheControl: SolutionCacheControl,
+		},
+		{
+			name:                 "happy path, stale etag",
+			query:                "?x=2&y=100&z=96",
+			ifNoneMatch:          `"stale"`,
+			expectedOutput:       "{\"solution\":[{\"step\":1,\"bucketX\":0,\"bucketY\":100,\"action\":\"Fill bucket Y\"},{\"step\":2,\"bucketX\":2,\"bucketY\":98,\"action\":\"Transfer from bucket Y to X\"},{\"step\":3,\"bucketX\":0,\"bucketY\":98,\"action\":\"Empty bucket X\"},{\"step\":4,\"bucketX\":2,\"bucketY\":96,\"action\":\"Transfer from bucket Y to X\",\"status\":\"Solved\"}],\"strategy\":\"bfs\"}",
+			expectedStatusCode:   http.StatusOK,
+			expectedETag:         etag,
+			expectedCacheControl: SolutionCacheControl,
+		},
+		{
+			name:        "not modified",
+			query:       "?x=2&y=100&z=96",
+			ifNoneMatch: `"stale", ` + etag,
+			mockRetrieveSolutionFromCache: func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+				return nil, errors.New("solution should not be looked up")
+			},
+			expectedStatusCode:   http.StatusNotModified,
+			expectedETag:         etag,
+			expectedCacheControl: SolutionCacheControl,
+		},
+		{
+			name:               "invalid parameter",
+			query:              "?x=2&y=a&z=96",
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request","title":"Bad Request","status":400,"detail":"y must be an integer","instance":"measure?x=2&y=a&z=96"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "input validation error",
+			query:              "?x=2&y=10000&z=1",
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#validation-error","title":"Validation Failed","status":400,"detail":"the request has invalid fields","instance":"measure?x=2&y=10000&z=1","errors":[{"field":"y_capacity","error":"y_capacity must be less than 10,000"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "no solution",
+			query:              "?x=2&y=6&z=5",
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#no-solution","title":"No Solution","status":422,"detail":"no solution: 5 is not a multiple of gcd(2, 6) = 2","instance":"measure?x=2&y=6&z=5","reason":"gcd_mismatch","x_capacity":2,"y_capacity":6,"z_amount_wanted":5,"gcd":2}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:  "too many steps to render",
+			query: "?x=2&y=100&z=96&format=svg",
+			mockRetrieveSolutionFromCache: func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+				solution := &models.Solution{}
+				for i := 1; i <= render.MaxRenderedSteps+1; i++ {
+					solution.Steps = append(solution.Steps, &models.Step{Number: i, Action: "Fill bucket Y"})
+				}
+				return solution, nil
+			},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#too-many-steps","title":"Too Many Steps","status":422,"detail":"too many steps to render: the solution has 201 steps, more than the limit of 200","instance":"measure?x=2&y=100&z=96&format=svg"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:  "error when retrieving solution from cache",
+			query: "?x=2&y=100&z=96",
+			mockRetrieveSolutionFromCache: func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+				return nil, &cache.Error{Err: errors.New("get error")}
+			},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#service-unavailable","title":"Service Unavailable","status":503,"detail":"cache is unavailable","instance":"measure?x=2&y=100&z=96"}`,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			retrieveSolutionFromCache = tc.mockRetrieveSolutionFromCache
+			if retrieveSolutionFromCache == nil {
+				retrieveSolutionFromCache = func(ctx context.Context, cs cache.CacheService, newMeasurement *models.NewMeasurement) (*models.Solution, error) {
+					return nil, nil
+				}
+			}
+			storeSolutionInCache = func(ctx context.Context, cs cache.CacheService, measurement *models.NewMeasurement, solution *models.Solution, expiration time.Duration) error {
+				return nil
+			}
+			retrieveTreeFromCache = func(ctx context.Context, cs cache.CacheService, xCap, yCap int) (*measurement.Tree, error) {
+				return nil, nil
+			}
+			storeTreeInCache = func(ctx context.Context, cs cache.CacheService, xCap, yCap int, tree *measurement.Tree, expiration time.Duration) error {
+				return nil
+			}
+			req, err := http.NewRequest(http.MethodGet, "measure"+tc.query, nil)
+			require.NoError(t, err)
+			if tc.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tc.ifNoneMatch)
+			}
+			recorder := httptest.NewRecorder()
+			h := New(nil, measurement.DefaultStrategy)
+			handler := web.Adapt(testLog, h.MeasureQuery)
+			handler.ServeHTTP(recorder, req)
+			require.Equal(t, tc.expectedStatusCode, recorder.Code)
+			require.Equal(t, tc.expectedOutput, recorder.Body.String())
+			require.Equal(t, tc.expectedETag, recorder.Header().Get("ETag"))
+			require.Equal(t, tc.expectedCacheControl, recorder.Header().Get("Cache-Control"))
+		})
+	}
+}
+
+func TestSolutionETag(t *testing.T) {
+	newMeasurement := &models.NewMeasurement{XCap: 2, YCap: 100, ZAmountWanted: 96, Strategy: measurement.BFS}
+	etag := solutionETag(httptest.NewRecorder(), newMeasurement, formatJSON)
+	require.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	require.Equal(t, etag, solutionETag(httptest.NewRecorder(), newMeasurement, formatJSON))
+	others := []string{
+		solutionETag(httptest.NewRecorder(), &models.NewMeasurement{XCap: 2, YCap: 100, ZAmountWanted: 94, Strategy: measurement.BFS}, formatJSON),
+		solutionETag(httptest.NewRecorder(), &models.NewMeasurement{XCap: 2, YCap: 100, ZAmountWanted: 96, Strategy: measurement.Math}, formatJSON),
+		solutionETag(httptest.NewRecorder(), newMeasurement, formatSVG),
+	}
+	gzipped := httptest.NewRecorder()
+	gzipped.Header().Set("Content-Encoding", "gzip")
+	others = append(others, solutionETag(gzipped, newMeasurement, formatJSON))
+	for _, other := range others {
+		require.NotEqual(t, etag, other)
+	}
+}
@@ -9,18 +9,24 @@ package integrationtest
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/require"
 	"github.com/tiagomelo/golang-waterjug-api/cache"
 	"github.com/tiagomelo/golang-waterjug-api/config"
 	"github.com/tiagomelo/golang-waterjug-api/handlers"
+	"github.com/tiagomelo/golang-waterjug-api/jobs"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
 )
 
 var testServer *httptest.Server
@@ -38,10 +44,13 @@ func TestMain(m *testing.M) {
 		os.Exit(1)
 	}
 	log := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	jobPool := jobs.NewPool(redisCache, log, 1)
+	defer jobPool.Stop()
 	apiMux := handlers.NewApiMux(
 		&handlers.ApiMuxConfig{
 			Cache: redisCache,
 			Log:   log,
+			Jobs:  jobPool,
 		},
 	)
 	testServer = httptest.NewServer(apiMux)
@@ -52,7 +61,7 @@ func TestMain(m *testing.M) {
 
 func TestMeasurement(t *testing.T) {
 	input := `{"x_capacity":2,"y_capacity":100,"z_amount_wanted":96}`
-	expectedOutput := `{"solution":[{"step":1,"bucketX":0,"bucketY":100,"action":"Fill bucket Y"},{"step":2,"bucketX":2,"bucketY":98,"action":"Transfer from bucket Y to X"},{"step":3,"bucketX":0,"bucketY":98,"action":"Empty bucket X"},{"step":4,"bucketX":2,"bucketY":96,"action":"Transfer from bucket Y to X","status":"Solved"}]}`
+	expectedOutput := `{"solution":[{"step":1,"bucketX":0,"bucketY":100,"action":"Fill bucket Y"},{"step":2,"bucketX":2,"bucketY":98,"action":"Transfer from bucket Y to X"},{"step":3,"bucketX":0,"bucketY":98,"action":"Empty bucket X"},{"step":4,"bucketX":2,"bucketY":96,"action":"Transfer from bucket Y to X","status":"Solved"}],"strategy":"bfs"}`
 	resp, err := http.Post(testServer.URL+"/v1/measure", "application/json", bytes.NewBuffer([]byte(input)))
 	require.NoError(t, err)
 	defer resp.Body.Close()
@@ -61,3 +70,39 @@ func TestMeasurement(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, expectedOutput, string(b))
 }
+
+func TestJob(t *testing.T) {
+	input := `{"x_capacity":2,"y_capacity":100,"z_amount_wanted":96,"strategy":"bfs"}`
+	expectedSolution := `{"solution":[{"step":1,"bucketX":0,"bucketY":100,"action":"Fill bucket Y"},{"step":2,"bucketX":2,"bucketY":98,"action":"Transfer from bucket Y to X"},{"step":3,"bucketX":0,"bucketY":98,"action":"Empty bucket X"},{"step":4,"bucketX":2,"bucketY":96,"action":"Transfer from bucket Y to X","status":"Solved"}],"strategy":"bfs"}`
+	resp, err := http.Post(testServer.URL+"/v1/jobs", "application/json", bytes.NewBuffer([]byte(input)))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	statusURL := resp.Header.Get("Location")
+	var job models.Job
+	require.Eventually(t, func() bool {
+		resp, err := http.Get(testServer.URL + statusURL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&job))
+		return job.Status == jobs.StatusSucceeded
+	}, 5*time.Second, 10*time.Millisecond)
+	solution, err := json.Marshal(job.Solution)
+	require.NoError(t, err)
+	require.Equal(t, expectedSolution, string(solution))
+}
+
+func TestPlay(t *testing.T) {
+	url := "ws" + strings.TrimPrefix(testServer.URL, "http") + "/v1/play?x=1&y=2&z=2"
+	// upgrades must get through the compression middleware untouched.
+	conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"Accept-Encoding": {"gzip"}})
+	require.NoError(t, err)
+	defer conn.Close()
+	var event models.PlayEvent
+	require.NoError(t, conn.ReadJSON(&event))
+	require.Equal(t, models.PlayEvent{Type: "state"}, event)
+	require.NoError(t, conn.WriteJSON(&models.PlayMessage{Type: "action", Action: "Fill bucket Y"}))
+	require.NoError(t, conn.ReadJSON(&event))
+	require.Equal(t, models.PlayEvent{Type: "win", BucketY: 2, Moves: 1, Action: "Fill bucket Y", Optimum: 1}, event)
+}
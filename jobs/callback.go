@@ -0,0 +1,130 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"time"
+
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// SignatureHeader is the header carrying the signature of the jobs posted to
+// callbacks.
+const SignatureHeader = "X-Waterjug-Signature"
+
+// TimestampHeader is the header carrying the time jobs were posted to
+// callbacks, in seconds since the Unix epoch.
+const TimestampHeader = "X-Waterjug-Timestamp"
+
+// CallbackAttempts is the maximum number of attempts to post a finished job
+// to its callback.
+const CallbackAttempts = 5
+
+// CallbackBackoff is the time waited before the first retry to post a
+// finished job, doubled after every failed attempt.
+const CallbackBackoff = time.Second
+
+// CallbackTimeout is the maximum time an attempt to post a finished job can take.
+const CallbackTimeout = 10 * time.Second
+
+// ErrForbiddenAddress is returned when a callback resolves to an address
+// that isn't public, such as a loopback, private or link-local one.
+var ErrForbiddenAddress = errors.New("callback address is not public")
+
+// Sign returns the signature of body posted at timestamp, in seconds since
+// the Unix epoch, with the given secret, as sent in the SignatureHeader:
+// "sha256=" followed by the hex-encoded HMAC-SHA256 of the timestamp, a dot
+// and the body. Callbacks verify it by signing the TimestampHeader and the
+// body they receive in the same way, and reject old timestamps so that
+// deliveries can't be replayed.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// publicAddress checks that address, a resolved IP address and port, is
+// public, so that callbacks can't be used to reach the server's own network.
+func publicAddress(address string) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	ip := addrPort.Addr().Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() ||
+		ip.IsMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsLinkLocalMulticast() {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, ip)
+	}
+	return nil
+}
+
+// deliver posts the finished job to its callback URL, retrying with
+// exponential backoff until the callback accepts it with a 2xx status code,
+// refuses it with a 4xx one other than 429 Too Many Requests, CallbackAttempts
+// attempts are made or the pool is stopped. Every attempt is recorded in the job.
+func (p *Pool) deliver(job *models.Job) {
+	defer p.wg.Done()
+	ctx := context.Background()
+	body, err := json.Marshal(job)
+	if err != nil {
+		p.log.Error("failed to serialize job", slog.String("id", job.ID), slog.String("error", err.Error()))
+		return
+	}
+	backoff := CallbackBackoff
+	for attempt := 1; ; attempt++ {
+		delivery := &models.Delivery{Attempt: attempt, At: now().UTC()}
+		retry := post(ctx, job.CallbackURL, body, job.CallbackSecret, delivery)
+		job.Deliveries = append(job.Deliveries, delivery)
+		if !p.update(ctx, job) || !retry || attempt == CallbackAttempts {
+			return
+		}
+		select {
+		case <-after(backoff):
+		case <-p.done:
+			return
+		}
+		backoff *= 2
+	}
+}
+
+// post makes an attempt to post body to url, signed with secret at the time
+// of the delivery, recording its outcome in delivery, and tells whether it's
+// worth retrying.
+func post(ctx context.Context, url string, body []byte, secret string, delivery *models.Delivery) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		delivery.Error = err.Error()
+		return false
+	}
+	timestamp := delivery.At.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(secret, timestamp, body))
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		delivery.Error = err.Error()
+		// callbacks resolving to forbidden addresses won't be allowed on retries.
+		return !errors.Is(err, ErrForbiddenAddress)
+	}
+	defer resp.Body.Close()
+	// the response is of no interest, but reading it lets the connection be reused.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	delivery.StatusCode = resp.StatusCode
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
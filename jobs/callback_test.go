@@ -0,0 +1,277 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+func TestSign(t *testing.T) {
+	require.Equal(t, "sha256=2f86d0e4bbadc52e3301f7b944aa989f72b54877140860e28709fc048fbeed46",
+		Sign("It's a Secret to Everybody", 1704067200, []byte("Hello, World!")))
+}
+
+func TestPublicAddress(t *testing.T) {
+	testCases := []struct {
+		name          string
+		address       string
+		expectedError string
+	}{
+		{
+			name:    "public IPv4 address",
+			address: "93.184.216.34:443",
+		},
+		{
+			name:    "public IPv6 address",
+			address: "[2606:2800:220:1:248:1893:25c8:1946]:443",
+		},
+		{
+			name:          "loopback address",
+			address:       "127.0.0.1:8080",
+			expectedError: "callback address is not public: 127.0.0.1",
+		},
+		{
+			name:          "IPv6 loopback address",
+			address:       "[::1]:8080",
+			expectedError: "callback address is not public: ::1",
+		},
+		{
+			name:          "private address",
+			address:       "10.0.0.1:80",
+			expectedError: "callback address is not public: 10.0.0.1",
+		},
+		{
+			name:          "link-local address",
+			address:       "169.254.169.254:80",
+			expectedError: "callback address is not public: 169.254.169.254",
+		},
+		{
+			name:          "IPv4-mapped private address",
+			address:       "[::ffff:192.168.0.1]:80",
+			expectedError: "callback address is not public: 192.168.0.1",
+		},
+		{
+			name:          "unspecified address",
+			address:       "0.0.0.0:80",
+			expectedError: "callback address is not public: 0.0.0.0",
+		},
+		{
+			name:          "not an address",
+			address:       "localhost:80",
+			expectedError: `ParseAddr("localhost"): unable to parse IP`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := publicAddress(tc.address)
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+// allowLoopback lets callbacks be served by test servers for the rest of the test.
+func allowLoopback(t *testing.T) {
+	originalCheckAddress := checkAddress
+	t.Cleanup(func() {
+		checkAddress = originalCheckAddress
+	})
+	checkAddress = func(address string) error {
+		return nil
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	testCases := []struct {
+		name                string
+		newMeasurement      *models.NewMeasurement
+		statusCodes         []int
+		expectedStatus      string
+		expectedStatusCodes []int
+		expectedBackoffs    []time.Duration
+	}{
+		{
+			name:                "happy path",
+			newMeasurement:      &models.NewMeasurement{XCap: 1, YCap: 2, ZAmountWanted: 2, Strategy: "bfs"},
+			statusCodes:         []int{http.StatusNoContent},
+			expectedStatus:      StatusSucceeded,
+			expectedStatusCodes: []int{http.StatusNoContent},
+		},
+		{
+			name:                "failed job",
+			newMeasurement:      &models.NewMeasurement{XCap: 2, YCap: 6, ZAmountWanted: 5, Strategy: "bfs"},
+			statusCodes:         []int{http.StatusOK},
+			expectedStatus:      StatusFailed,
+			expectedStatusCodes: []int{http.StatusOK},
+		},
+		{
+			name:                "retries",
+			newMeasurement:      &models.NewMeasurement{XCap: 1, YCap: 2, ZAmountWanted: 2, Strategy: "bfs"},
+			statusCodes:         []int{http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusOK},
+			expectedStatus:      StatusSucceeded,
+			expectedStatusCodes: []int{http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusOK},
+			expectedBackoffs:    []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:           "gives up",
+			newMeasurement: &models.NewMeasurement{XCap: 1, YCap: 2, ZAmountWanted: 2, Strategy: "bfs"},
+			statusCodes:    []int{http.StatusServiceUnavailable},
+			expectedStatus: StatusSucceeded,
+			expectedStatusCodes: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable,
+				http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+			expectedBackoffs: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second},
+		},
+		{
+			name:                "refused",
+			newMeasurement:      &models.NewMeasurement{XCap: 1, YCap: 2, ZAmountWanted: 2, Strategy: "bfs"},
+			statusCodes:         []int{http.StatusGone},
+			expectedStatus:      StatusSucceeded,
+			expectedStatusCodes: []int{http.StatusGone},
+		},
+	}
+	originalAfter := after
+	defer func() {
+		after = originalAfter
+	}()
+	allowLoopback(t)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				mu       sync.Mutex
+				backoffs []time.Duration
+				received []*models.Job
+			)
+			after = func(d time.Duration) <-chan time.Time {
+				mu.Lock()
+				backoffs = append(backoffs, d)
+				mu.Unlock()
+				ch := make(chan time.Time, 1)
+				ch <- time.Time{}
+				return ch
+			}
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				timestamp, err := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+				require.NoError(t, err)
+				require.WithinDuration(t, time.Now(), time.Unix(timestamp, 0), time.Minute)
+				require.Equal(t, Sign("It's a Secret to Everybody", timestamp, body), r.Header.Get(SignatureHeader))
+				var job models.Job
+				require.NoError(t, json.Unmarshal(body, &job))
+				mu.Lock()
+				received = append(received, &job)
+				statusCode := tc.statusCodes[min(len(received), len(tc.statusCodes))-1]
+				mu.Unlock()
+				w.WriteHeader(statusCode)
+			}))
+			defer receiver.Close()
+			p := NewPool(newMemoryCache(), testLog, 1)
+			defer p.Stop()
+			job := newJob("42", tc.newMeasurement)
+			job.CallbackURL = receiver.URL
+			job.CallbackSecret = "It's a Secret to Everybody"
+			require.NoError(t, p.Submit(context.TODO(), job))
+			var stored *models.Job
+			require.Eventually(t, func() bool {
+				var err error
+				stored, err = p.Get(context.TODO(), "42")
+				require.NoError(t, err)
+				return len(stored.Deliveries) == len(tc.expectedStatusCodes)
+			}, 5*time.Second, time.Millisecond)
+			require.Equal(t, tc.expectedStatus, stored.Status)
+			statusCodes := make([]int, 0, len(stored.Deliveries))
+			for i, delivery := range stored.Deliveries {
+				require.Equal(t, i+1, delivery.Attempt)
+				require.Empty(t, delivery.Error)
+				statusCodes = append(statusCodes, delivery.StatusCode)
+			}
+			require.Equal(t, tc.expectedStatusCodes, statusCodes)
+			mu.Lock()
+			defer mu.Unlock()
+			require.Equal(t, tc.expectedBackoffs, backoffs)
+			for _, job := range received {
+				require.Equal(t, tc.expectedStatus, job.Status)
+				require.Empty(t, job.Deliveries)
+				require.Empty(t, job.CallbackSecret)
+			}
+		})
+	}
+}
+
+func TestDeliverUnreachableCallback(t *testing.T) {
+	originalAfter := after
+	defer func() {
+		after = originalAfter
+	}()
+	after = func(d time.Duration) <-chan time.Time {
+		ch := make(chan time.Time, 1)
+		ch <- time.Time{}
+		return ch
+	}
+	allowLoopback(t)
+	receiver := httptest.NewServer(http.NotFoundHandler())
+	receiver.Close()
+	p := NewPool(newMemoryCache(), testLog, 1)
+	defer p.Stop()
+	job := newJob("42", &models.NewMeasurement{XCap: 1, YCap: 2, ZAmountWanted: 2, Strategy: "bfs"})
+	job.CallbackURL = receiver.URL
+	job.CallbackSecret = "It's a Secret to Everybody"
+	require.NoError(t, p.Submit(context.TODO(), job))
+	require.Eventually(t, func() bool {
+		stored, err := p.Get(context.TODO(), "42")
+		require.NoError(t, err)
+		return len(stored.Deliveries) == CallbackAttempts
+	}, 5*time.Second, time.Millisecond)
+	stored, err := p.Get(context.TODO(), "42")
+	require.NoError(t, err)
+	for _, delivery := range stored.Deliveries {
+		require.Zero(t, delivery.StatusCode)
+		require.Contains(t, delivery.Error, "connection refused")
+	}
+}
+
+func TestDeliverForbiddenAddress(t *testing.T) {
+	var called atomic.Bool
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called.Store(true)
+	}))
+	defer receiver.Close()
+	p := NewPool(newMemoryCache(), testLog, 1)
+	defer p.Stop()
+	job := newJob("42", &models.NewMeasurement{XCap: 1, YCap: 2, ZAmountWanted: 2, Strategy: "bfs"})
+	job.CallbackURL = receiver.URL
+	job.CallbackSecret = "It's a Secret to Everybody"
+	require.NoError(t, p.Submit(context.TODO(), job))
+	var stored *models.Job
+	require.Eventually(t, func() bool {
+		var err error
+		stored, err = p.Get(context.TODO(), "42")
+		require.NoError(t, err)
+		return len(stored.Deliveries) > 0
+	}, 5*time.Second, time.Millisecond)
+	p.Stop()
+	stored, err := p.Get(context.TODO(), "42")
+	require.NoError(t, err)
+	require.Len(t, stored.Deliveries, 1)
+	require.Zero(t, stored.Deliveries[0].StatusCode)
+	require.Contains(t, stored.Deliveries[0].Error, "callback address is not public: 127.0.0.1")
+	require.False(t, called.Load())
+}
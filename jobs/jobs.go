@@ -0,0 +1,309 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/tiagomelo/golang-waterjug-api/cache"
+	"github.com/tiagomelo/golang-waterjug-api/measurement"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+	"github.com/tiagomelo/golang-waterjug-api/web"
+)
+
+// Statuses of a job.
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusCanceled  = "canceled"
+)
+
+// JobExpiration is the expiration time for jobs kept in the cache (24 hours).
+const JobExpiration = 24 * time.Hour
+
+// SolutionExpiration is the expiration time for solutions of jobs stored in
+// the cache (24 hours).
+const SolutionExpiration = 24 * time.Hour
+
+// QueueSize is the maximum number of jobs waiting for a worker.
+const QueueSize = 100
+
+// ProgressUpdateInterval is the minimum time between two progress updates of
+// a running job.
+const ProgressUpdateInterval = 250 * time.Millisecond
+
+var (
+	// ErrQueueFull is returned when a job is submitted while QueueSize
+	// jobs are already waiting for a worker.
+	ErrQueueFull = errors.New("job queue is full")
+	// ErrStopped is returned when a job is submitted to a stopped pool.
+	ErrStopped = errors.New("job pool is stopped")
+	// ErrNotFound is returned when a job doesn't exist or has expired.
+	ErrNotFound = errors.New("job not found")
+	// ErrFinished is returned when canceling a job that has already finished.
+	ErrFinished = errors.New("job has already finished")
+)
+
+// For ease of unit testing.
+var (
+	now   = time.Now
+	since = time.Since
+	after = time.After
+	// checkAddress tells whether callbacks may be reached at an address.
+	checkAddress = publicAddress
+	// httpClient posts finished jobs to their callbacks, which must answer
+	// themselves rather than redirect. Addresses are checked as they're
+	// dialed, once resolved, and proxies are bypassed.
+	httpClient = &http.Client{
+		Timeout: CallbackTimeout,
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout: CallbackTimeout,
+				Control: func(network, address string, c syscall.RawConn) error {
+					return checkAddress(address)
+				},
+			}).DialContext,
+			ForceAttemptHTTP2:   true,
+			TLSHandshakeTimeout: CallbackTimeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+)
+
+// Pool runs jobs on a fixed number of workers. Jobs are kept in the cache,
+// so that any instance sharing it can report how they're going or cancel
+// them, but each runs on the instance it was submitted to.
+type Pool struct {
+	cache   cache.CacheService
+	log     *slog.Logger
+	queue   chan *models.Job
+	done    chan struct{}
+	mu      sync.Mutex // mu guards stopped and makes room in queue for the job being submitted.
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// NewPool creates a pool keeping jobs in the given cache and starts its workers.
+func NewPool(cs cache.CacheService, log *slog.Logger, workers int) *Pool {
+	p := &Pool{
+		cache: cs,
+		log:   log,
+		queue: make(chan *models.Job, QueueSize),
+		done:  make(chan struct{}),
+	}
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+	return p
+}
+
+// Stop stops the workers once they finish the jobs they're running, and
+// the deliveries of finished jobs once they finish their current attempts.
+// Jobs still queued won't run: they fail, for their clients to submit them
+// again, without being posted to their callbacks.
+func (p *Pool) Stop() {
+	p.mu.Lock()
+	if !p.stopped {
+		p.stopped = true
+		close(p.done)
+	}
+	p.mu.Unlock()
+	p.wg.Wait()
+	ctx := context.Background()
+	for len(p.queue) > 0 {
+		job := <-p.queue
+		job.Status = StatusFailed
+		job.Error, _ = json.Marshal(web.MapErrorAt(job.StatusURL, web.WithStatus(http.StatusServiceUnavailable, ErrStopped)))
+		p.update(ctx, job)
+	}
+}
+
+// Submit queues the given job, whose ID, status URL and valid measurement
+// are set, and stores it in the cache.
+func (p *Pool) Submit(ctx context.Context, job *models.Job) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopped {
+		return ErrStopped
+	}
+	if len(p.queue) == cap(p.queue) {
+		return ErrQueueFull
+	}
+	job.Status = StatusQueued
+	job.CreatedAt = now().UTC()
+	job.UpdatedAt = job.CreatedAt
+	if err := cache.StoreJob(ctx, p.cache, job, JobExpiration); err != nil {
+		return err
+	}
+	// workers only take jobs from the queue, so there's still room for it.
+	// They get a copy, for the caller to keep the job to itself.
+	queued := *job
+	p.queue <- &queued
+	return nil
+}
+
+// Get returns the job with the given ID.
+func (p *Pool) Get(ctx context.Context, id string) (*models.Job, error) {
+	job, err := cache.RetrieveJob(ctx, p.cache, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, ErrNotFound
+	}
+	return job, nil
+}
+
+// Cancel cancels the job with the given ID, unless it has already finished.
+// Queued jobs won't run. Running ones can't be interrupted, but their
+// outcome is discarded.
+func (p *Pool) Cancel(ctx context.Context, id string) (*models.Job, error) {
+	job, err := p.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch job.Status {
+	case StatusSucceeded, StatusFailed:
+		return job, ErrFinished
+	case StatusCanceled:
+		return job, nil
+	}
+	// the cancellation is recorded first: workers check it after storing
+	// their updates, so that the job ends up canceled whichever comes last.
+	if err := cache.StoreJobCancellation(ctx, p.cache, id, JobExpiration); err != nil {
+		return nil, err
+	}
+	job.Status = StatusCanceled
+	job.UpdatedAt = now().UTC()
+	if err := cache.StoreJob(ctx, p.cache, job, JobExpiration); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// work runs queued jobs until the pool is stopped.
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for {
+		select {
+		case <-p.done:
+			return
+		default:
+		}
+		select {
+		case job := <-p.queue:
+			p.run(job)
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// run runs the given job, keeping its state in the cache up to date.
+func (p *Pool) run(job *models.Job) {
+	ctx := context.Background()
+	job.Status = StatusRunning
+	if !p.update(ctx, job) {
+		return
+	}
+	solution, err := p.solve(ctx, job)
+	if err != nil {
+		problem := web.MapErrorAt(job.StatusURL, err)
+		level := slog.LevelInfo
+		if problem.Status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		p.log.LogAttrs(ctx, level, "job failed",
+			slog.String("id", job.ID),
+			slog.Int("status", problem.Status),
+			slog.String("error", err.Error()),
+		)
+		job.Status = StatusFailed
+		job.Error, _ = json.Marshal(problem)
+	} else {
+		job.Status = StatusSucceeded
+		job.Solution = solution
+	}
+	if p.update(ctx, job) && job.CallbackURL != "" {
+		p.wg.Add(1)
+		go p.deliver(job)
+	}
+}
+
+// solve returns the solution to the measurement of the given job, from the
+// cache when possible, reporting the progress of the search meanwhile.
+func (p *Pool) solve(ctx context.Context, job *models.Job) (*models.Solution, error) {
+	cachedSolution, err := cache.RetrieveSolution(ctx, p.cache, job.Measurement)
+	if err != nil {
+		return nil, err
+	}
+	if cachedSolution != nil {
+		return cachedSolution, nil
+	}
+	problem, err := measurement.NewProblem(job.Measurement)
+	if err != nil {
+		return nil, err
+	}
+	lastUpdate := now()
+	solution, err := measurement.MeasureWithProgress(job.Measurement.Strategy, problem, func(expanded, visited int) {
+		if since(lastUpdate) < ProgressUpdateInterval {
+			return
+		}
+		lastUpdate = now()
+		job.Progress = &models.JobProgress{Expanded: expanded, Visited: visited}
+		p.update(ctx, job)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := cache.StoreSolution(ctx, p.cache, job.Measurement, solution, SolutionExpiration); err != nil {
+		return nil, err
+	}
+	return solution, nil
+}
+
+// update stores the given job unless it has been canceled or has expired
+// meanwhile, telling whether it did. Since the job may be canceled while
+// it's being stored, its cancellation is checked once more afterwards, and
+// the job stored again as canceled, with its outcome discarded, if need be.
+// Failures are logged, as there is no one else to tell.
+func (p *Pool) update(ctx context.Context, job *models.Job) bool {
+	current, err := cache.RetrieveJob(ctx, p.cache, job.ID)
+	if err == nil && (current == nil || current.Status == StatusCanceled) {
+		return false
+	}
+	var canceled bool
+	if err == nil {
+		job.UpdatedAt = now().UTC()
+		err = cache.StoreJob(ctx, p.cache, job, JobExpiration)
+	}
+	if err == nil {
+		canceled, err = cache.IsJobCanceled(ctx, p.cache, job.ID)
+	}
+	if err == nil && canceled {
+		job.Status = StatusCanceled
+		job.Solution = nil
+		job.Error = nil
+		err = cache.StoreJob(ctx, p.cache, job, JobExpiration)
+	}
+	if err != nil {
+		p.log.Error("failed to update job", slog.String("id", job.ID), slog.String("error", err.Error()))
+		return false
+	}
+	return !canceled
+}
@@ -0,0 +1,305 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/cache"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+var testLog = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// memoryCache is a cache service keeping values in memory, along with
+// every job ever stored.
+type memoryCache struct {
+	mu     sync.Mutex
+	values map[string]string
+	jobs   []*models.Job
+	setErr error
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{values: make(map[string]string)}
+}
+
+func (m *memoryCache) Get(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.values[key], nil
+}
+
+func (m *memoryCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.setErr != nil {
+		return m.setErr
+	}
+	m.values[key] = value.(string)
+	if strings.HasPrefix(key, "job#") {
+		var job models.Job
+		if err := json.Unmarshal([]byte(value.(string)), &job); err != nil {
+			return err
+		}
+		m.jobs = append(m.jobs, &job)
+	}
+	return nil
+}
+
+// stored returns every job ever stored.
+func (m *memoryCache) stored() []*models.Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*models.Job(nil), m.jobs...)
+}
+
+func newJob(id string, newMeasurement *models.NewMeasurement) *models.Job {
+	return &models.Job{ID: id, StatusURL: "/v1/jobs/" + id, Measurement: newMeasurement}
+}
+
+// waitFor waits for the job with the given ID to finish.
+func waitFor(t *testing.T, p *Pool, id string) *models.Job {
+	var job *models.Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = p.Get(context.TODO(), id)
+		require.NoError(t, err)
+		return job.Status != StatusQueued && job.Status != StatusRunning
+	}, 5*time.Second, time.Millisecond)
+	return job
+}
+
+func TestPool(t *testing.T) {
+	testCases := []struct {
+		name             string
+		newMeasurement   *models.NewMeasurement
+		cachedSolution   string
+		expectedStatus   string
+		expectedSolution string
+		expectedError    string
+	}{
+		{
+			name:             "happy path",
+			newMeasurement:   &models.NewMeasurement{XCap: 1, YCap: 2, ZAmountWanted: 2, Strategy: "bfs"},
+			expectedStatus:   StatusSucceeded,
+			expectedSolution: `{"solution":[{"step":1,"bucketX":0,"bucketY":2,"action":"Fill bucket Y","status":"Solved"}],"strategy":"bfs"}`,
+		},
+		{
+			name:             "cached solution",
+			newMeasurement:   &models.NewMeasurement{XCap: 1, YCap: 2, ZAmountWanted: 2, Strategy: "bfs"},
+			cachedSolution:   `{"solution":[],"strategy":"cached"}`,
+			expectedStatus:   StatusSucceeded,
+			expectedSolution: `{"solution":[],"strategy":"cached"}`,
+		},
+		{
+			name:           "no solution",
+			newMeasurement: &models.NewMeasurement{XCap: 2, YCap: 6, ZAmountWanted: 5, Strategy: "bfs"},
+			expectedStatus: StatusFailed,
+			expectedError:  `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#no-solution","title":"No Solution","status":422,"detail":"no solution: 5 is not a multiple of gcd(2, 6) = 2","instance":"/v1/jobs/42","reason":"gcd_mismatch","x_capacity":2,"y_capacity":6,"z_amount_wanted":5,"gcd":2}`,
+		},
+		{
+			name:           "unknown strategy",
+			newMeasurement: &models.NewMeasurement{XCap: 1, YCap: 2, ZAmountWanted: 2, Strategy: "dfs"},
+			expectedStatus: StatusFailed,
+			expectedError:  `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#internal-error","title":"Internal Server Error","status":500,"instance":"/v1/jobs/42"}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cs := newMemoryCache()
+			if tc.cachedSolution != "" {
+				cs.values["1#2#2#bfs#"] = tc.cachedSolution
+			}
+			p := NewPool(cs, testLog, 2)
+			defer p.Stop()
+			require.NoError(t, p.Submit(context.TODO(), newJob("42", tc.newMeasurement)))
+			job := waitFor(t, p, "42")
+			require.Equal(t, tc.expectedStatus, job.Status)
+			if tc.expectedSolution != "" {
+				solution, err := json.Marshal(job.Solution)
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedSolution, string(solution))
+			}
+			require.Equal(t, tc.expectedError, string(job.Error))
+			statuses := make([]string, 0, 3)
+			for _, stored := range cs.stored() {
+				statuses = append(statuses, stored.Status)
+			}
+			require.Equal(t, []string{StatusQueued, StatusRunning, tc.expectedStatus}, statuses)
+		})
+	}
+}
+
+func TestPoolProgress(t *testing.T) {
+	originalSince := since
+	defer func() {
+		since = originalSince
+	}()
+	since = func(time.Time) time.Duration { return ProgressUpdateInterval }
+	cs := newMemoryCache()
+	p := NewPool(cs, testLog, 1)
+	defer p.Stop()
+	require.NoError(t, p.Submit(context.TODO(), newJob("42", &models.NewMeasurement{XCap: 997, YCap: 1000, ZAmountWanted: 500, Strategy: "bfs"})))
+	require.Equal(t, StatusSucceeded, waitFor(t, p, "42").Status)
+	var reports int
+	for _, stored := range cs.stored() {
+		if stored.Status == StatusRunning && stored.Progress != nil {
+			reports++
+			require.Positive(t, stored.Progress.Expanded)
+			require.GreaterOrEqual(t, stored.Progress.Visited, stored.Progress.Expanded)
+		}
+	}
+	require.Positive(t, reports)
+}
+
+func TestSubmit(t *testing.T) {
+	t.Run("queue full", func(t *testing.T) {
+		p := NewPool(newMemoryCache(), testLog, 0)
+		for i := 0; i < QueueSize; i++ {
+			require.NoError(t, p.Submit(context.TODO(), newJob("42", &models.NewMeasurement{XCap: 1, YCap: 2, ZAmountWanted: 2})))
+		}
+		require.ErrorIs(t, p.Submit(context.TODO(), newJob("43", &models.NewMeasurement{XCap: 1, YCap: 2, ZAmountWanted: 2})), ErrQueueFull)
+	})
+	t.Run("stopped", func(t *testing.T) {
+		p := NewPool(newMemoryCache(), testLog, 1)
+		p.Stop()
+		require.ErrorIs(t, p.Submit(context.TODO(), newJob("42", &models.NewMeasurement{XCap: 1, YCap: 2, ZAmountWanted: 2})), ErrStopped)
+	})
+	t.Run("error when storing job", func(t *testing.T) {
+		cs := newMemoryCache()
+		cs.setErr = &cache.Error{Err: errors.New("set error")}
+		p := NewPool(cs, testLog, 0)
+		err := p.Submit(context.TODO(), newJob("42", &models.NewMeasurement{XCap: 1, YCap: 2, ZAmountWanted: 2}))
+		require.EqualError(t, err, "set error")
+		require.Empty(t, p.queue)
+	})
+}
+
+func TestCancel(t *testing.T) {
+	testCases := []struct {
+		name           string
+		status         string
+		id             string
+		expectedStatus string
+		expectedError  error
+	}{
+		{
+			name:           "queued job",
+			status:         StatusQueued,
+			id:             "42",
+			expectedStatus: StatusCanceled,
+		},
+		{
+			name:           "running job",
+			status:         StatusRunning,
+			id:             "42",
+			expectedStatus: StatusCanceled,
+		},
+		{
+			name:           "canceled job",
+			status:         StatusCanceled,
+			id:             "42",
+			expectedStatus: StatusCanceled,
+		},
+		{
+			name:           "finished job",
+			status:         StatusSucceeded,
+			id:             "42",
+			expectedStatus: StatusSucceeded,
+			expectedError:  ErrFinished,
+		},
+		{
+			name:          "unknown job",
+			status:        StatusQueued,
+			id:            "43",
+			expectedError: ErrNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cs := newMemoryCache()
+			p := NewPool(cs, testLog, 0)
+			job := newJob("42", &models.NewMeasurement{XCap: 1, YCap: 2, ZAmountWanted: 2})
+			job.Status = tc.status
+			require.NoError(t, cache.StoreJob(context.TODO(), cs, job, JobExpiration))
+			output, err := p.Cancel(context.TODO(), tc.id)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error to occur, got "%v"`, err)
+				}
+				require.ErrorIs(t, err, tc.expectedError)
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error to occur, got nil`)
+				}
+			}
+			if tc.expectedStatus != "" {
+				require.Equal(t, tc.expectedStatus, output.Status)
+				stored, err := p.Get(context.TODO(), "42")
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedStatus, stored.Status)
+			}
+		})
+	}
+}
+
+func TestRunCanceledJob(t *testing.T) {
+	cs := newMemoryCache()
+	p := NewPool(cs, testLog, 0)
+	job := newJob("42", &models.NewMeasurement{XCap: 1, YCap: 2, ZAmountWanted: 2, Strategy: "bfs"})
+	require.NoError(t, p.Submit(context.TODO(), job))
+	_, err := p.Cancel(context.TODO(), "42")
+	require.NoError(t, err)
+	p.run(<-p.queue)
+	stored, err := p.Get(context.TODO(), "42")
+	require.NoError(t, err)
+	require.Equal(t, StatusCanceled, stored.Status)
+	require.Nil(t, stored.Solution)
+}
+
+func TestUpdateCanceledMeanwhile(t *testing.T) {
+	cs := newMemoryCache()
+	p := NewPool(cs, testLog, 0)
+	job := newJob("42", &models.NewMeasurement{XCap: 1, YCap: 2, ZAmountWanted: 2, Strategy: "bfs"})
+	job.Status = StatusRunning
+	require.NoError(t, cache.StoreJob(context.TODO(), cs, job, JobExpiration))
+	// the job is being canceled: its cancellation is recorded, but
+	// the canceled job hasn't been stored yet.
+	require.NoError(t, cache.StoreJobCancellation(context.TODO(), cs, "42", JobExpiration))
+	job.Status = StatusSucceeded
+	job.Solution = &models.Solution{Strategy: "bfs"}
+	require.False(t, p.update(context.TODO(), job))
+	stored, err := p.Get(context.TODO(), "42")
+	require.NoError(t, err)
+	require.Equal(t, StatusCanceled, stored.Status)
+	require.Nil(t, stored.Solution)
+}
+
+func TestStopFailsQueuedJobs(t *testing.T) {
+	p := NewPool(newMemoryCache(), testLog, 0)
+	require.NoError(t, p.Submit(context.TODO(), newJob("42", &models.NewMeasurement{XCap: 1, YCap: 2, ZAmountWanted: 2, Strategy: "bfs"})))
+	require.NoError(t, p.Submit(context.TODO(), newJob("43", &models.NewMeasurement{XCap: 1, YCap: 2, ZAmountWanted: 2, Strategy: "bfs"})))
+	_, err := p.Cancel(context.TODO(), "43")
+	require.NoError(t, err)
+	p.Stop()
+	require.Empty(t, p.queue)
+	stored, err := p.Get(context.TODO(), "42")
+	require.NoError(t, err)
+	require.Equal(t, StatusFailed, stored.Status)
+	require.JSONEq(t, `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#service-unavailable","title":"Service Unavailable","status":503,"detail":"job pool is stopped","instance":"/v1/jobs/42"}`, string(stored.Error))
+	stored, err = p.Get(context.TODO(), "43")
+	require.NoError(t, err)
+	require.Equal(t, StatusCanceled, stored.Status)
+}
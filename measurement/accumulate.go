@@ -0,0 +1,102 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import "github.com/tiagomelo/golang-waterjug-api/measurement/models"
+
+// MaxAccumulationStates is the largest number of states discovered while
+// searching in accumulation mode. The receiver's level adds a dimension to the
+// states, so that their number grows with the product of the capacities' sum
+// and the target.
+const MaxAccumulationStates = 100_000
+
+// receiverActionNames lists the actions pouring a jug into the receiving vessel.
+var receiverActionNames = []string{
+	"Pour bucket X into receiver",
+	"Pour bucket Y into receiver",
+}
+
+// Actions returns the names of every action, including the ones pouring
+// jugs into the receiving vessel in accumulation mode.
+func Actions() []string {
+	return append(append([]string{}, actions...), receiverActionNames...)
+}
+
+// receiverActions returns the states reachable from the current state by
+// pouring either jug into the receiving vessel, when it's not empty.
+func receiverActions(currentState *state) []*state {
+	var nextStates []*state
+	if currentState.x > 0 {
+		nextStates = append(nextStates, &state{x: 0, y: currentState.y, receiver: currentState.receiver + currentState.x,
+			depth: currentState.depth + 1, action: receiverActionNames[0], prev: currentState})
+	}
+	if currentState.y > 0 {
+		nextStates = append(nextStates, &state{x: currentState.x, y: 0, receiver: currentState.receiver + currentState.y,
+			depth: currentState.depth + 1, action: receiverActionNames[1], prev: currentState})
+	}
+	return nextStates
+}
+
+// accumulationBFS performs a breadth-first search for the minimum steps
+// required to deliver exactly p.Target into a receiving vessel, using two
+// jugs with capacities p.XCap and p.YCap. Water can't be taken back from
+// the receiver, so states where it holds more than the target are dropped.
+// Once more than MaxAccumulationStates states are discovered, the search
+// gives up with a *NoSolutionError.
+func accumulationBFS(p Problem, stats *searchStats) (*state, error) {
+	if !p.feasible() {
+		return nil, nil
+	}
+	visited := map[[3]int]bool{{0, 0, 0}: true}
+	queue := []*state{initialState()}
+	stats.discover(queue[0])
+	stats.frontier(len(queue))
+	for len(queue) > 0 {
+		currentState := queue[0]
+		queue = queue[1:]
+		stats.expand()
+		nextStates := append(transitions(currentState, p.XCap, p.YCap), receiverActions(currentState)...)
+		for _, nextState := range p.prioritize(nextStates) {
+			if p.exceedsMaxSteps(nextState) {
+				return nil, nil // every state within the limit was checked.
+			}
+			if nextState.receiver > p.Target {
+				continue
+			}
+			if nextState.receiver == p.Target {
+				stats.discover(nextState)
+				nextState.status = "Solved"
+				return nextState, nil // found the solution.
+			}
+			key := [3]int{nextState.x, nextState.y, nextState.receiver}
+			if !visited[key] {
+				if len(visited) == MaxAccumulationStates {
+					return nil, p.statesExhausted(MaxAccumulationStates)
+				}
+				visited[key] = true
+				stats.discover(nextState)
+				queue = append(queue, nextState)
+			}
+		}
+		stats.frontier(len(queue))
+	}
+	return nil, nil
+}
+
+// accumulationSolutionFrom builds the solution leading to the given state,
+// reporting the receiver's level at every step.
+func accumulationSolutionFrom(s *state) *models.Solution {
+	solution := solutionFrom(s)
+	if solution == nil {
+		return nil
+	}
+	i := len(solution.Steps) - 1
+	for stateStep := s; stateStep.prev != nil; stateStep = stateStep.prev {
+		receiver := stateStep.receiver
+		solution.Steps[i].Receiver = &receiver
+		i--
+	}
+	return solution
+}
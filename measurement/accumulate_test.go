@@ -0,0 +1,102 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+func intSlicePtrs(values ...int) []*int {
+	ptrs := make([]*int, len(values))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	return ptrs
+}
+
+func TestAccumulation(t *testing.T) {
+	testCases := []struct {
+		name              string
+		problem           Problem
+		expectedActions   []string
+		expectedReceivers []*int
+		expectedError     error
+	}{
+		{
+			name:    "13 litres with 3 and 5 litre jugs",
+			problem: Problem{XCap: 3, YCap: 5, Target: 13, Accumulate: true},
+			expectedActions: []string{
+				"Fill bucket X",
+				"Fill bucket Y",
+				"Pour bucket X into receiver",
+				"Pour bucket Y into receiver",
+				"Fill bucket Y",
+				"Pour bucket Y into receiver",
+			},
+			expectedReceivers: intSlicePtrs(0, 0, 3, 8, 8, 13),
+		},
+		{
+			name:    "amount that needs transfers between jugs",
+			problem: Problem{XCap: 3, YCap: 5, Target: 1, Accumulate: true},
+			expectedActions: []string{
+				"Fill bucket X",
+				"Transfer from bucket X to Y",
+				"Fill bucket X",
+				"Transfer from bucket X to Y",
+				"Pour bucket X into receiver",
+			},
+			expectedReceivers: intSlicePtrs(0, 0, 0, 0, 1),
+		},
+		{
+			name:          "amount that is not a multiple of the gcd",
+			problem:       Problem{XCap: 4, YCap: 6, Target: 9, Accumulate: true},
+			expectedError: &NoSolutionError{Reason: ReasonGCDMismatch, XCap: 4, YCap: 6, Target: 9, GCD: 2},
+		},
+		{
+			name:          "more states than the search budget",
+			problem:       Problem{XCap: 397, YCap: 401, Target: 9999, Accumulate: true},
+			expectedError: &NoSolutionError{Reason: ReasonBudgetExhausted, XCap: 397, YCap: 401, Target: 9999, MaxStates: MaxAccumulationStates},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := MeasureWith(BFS, tc.problem)
+			if tc.expectedError != nil {
+				require.Equal(t, tc.expectedError, err)
+				return
+			}
+			require.NoError(t, err)
+			var actions []string
+			var receivers []*int
+			for _, step := range output.Steps {
+				actions = append(actions, step.Action)
+				receivers = append(receivers, step.Receiver)
+			}
+			require.Equal(t, tc.expectedActions, actions)
+			require.Equal(t, tc.expectedReceivers, receivers)
+			require.Equal(t, "Solved", output.Steps[len(output.Steps)-1].Status)
+		})
+	}
+}
+
+func TestAccumulationUnsupportedStrategies(t *testing.T) {
+	for _, strategy := range []string{Bidirectional, Math, AStar} {
+		t.Run(strategy, func(t *testing.T) {
+			_, err := MeasureWith(strategy, Problem{XCap: 3, YCap: 5, Target: 13, Accumulate: true})
+			require.ErrorIs(t, err, ErrUnsupportedGoal)
+		})
+	}
+	_, err := BuildTree(3, 5).Solve(Problem{XCap: 3, YCap: 5, Target: 13, Accumulate: true})
+	require.ErrorIs(t, err, ErrUnsupportedGoal)
+}
+
+func TestNewProblemAccumulation(t *testing.T) {
+	p, err := NewProblem(&models.NewMeasurement{XCap: 3, YCap: 5, ZAmountWanted: 13, Accumulate: true})
+	require.NoError(t, err)
+	require.True(t, p.Accumulate)
+}
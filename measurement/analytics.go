@@ -0,0 +1,125 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// maxDistance performs a breadth-first search from the state at index from
+// of a graph given by the successors of each of its states, filling dist with
+// the number of steps needed to reach every state. It returns the largest of them.
+func maxDistance(successors [][]int, from int, dist []int) int {
+	for i := range dist {
+		dist[i] = -1
+	}
+	dist[from] = 0
+	order := []int{from}
+	for i := 0; i < len(order); i++ {
+		for _, next := range successors[order[i]] {
+			if dist[next] < 0 {
+				dist[next] = dist[order[i]] + 1
+				order = append(order, next)
+			}
+		}
+	}
+	return dist[order[len(order)-1]]
+}
+
+// reachableGraph returns every state reachable from the initial one, in
+// discovery order, the initial state first, along with the successors of each
+// of them, identified by their index. Graphs of more than MaxGraphStates
+// states are rejected.
+func reachableGraph(xMax, yMax int) ([][2]int, [][]int, error) {
+	index := map[[2]int]int{{0, 0}: 0}
+	states := [][2]int{{0, 0}}
+	var successors [][]int
+	for i := 0; i < len(states); i++ {
+		next := make([]int, 0, len(actions))
+		for action := range actions {
+			x, y := move(states[i][0], states[i][1], xMax, yMax, action)
+			j, ok := index[[2]int{x, y}]
+			if !ok {
+				if len(states) == MaxGraphStates {
+					return nil, nil, fmt.Errorf("%w: more than %d reachable states", ErrGraphTooLarge, MaxGraphStates)
+				}
+				j = len(states)
+				index[[2]int{x, y}] = j
+				states = append(states, [2]int{x, y})
+			}
+			next = append(next, j)
+		}
+		successors = append(successors, next)
+	}
+	return states, successors, nil
+}
+
+// unreachableRanges returns the states of jugs of capacities xMax and yMax
+// other than the given ones, as ranges of amounts of water in jug Y for
+// each amount of water in jug X.
+func unreachableRanges(xMax, yMax int, reachable [][2]int) []*models.StateRange {
+	columns := make([][]int, xMax+1)
+	for _, state := range reachable {
+		columns[state[0]] = append(columns[state[0]], state[1])
+	}
+	ranges := []*models.StateRange{}
+	for x, column := range columns {
+		slices.Sort(column)
+		from := 0
+		for _, y := range append(column, yMax+1) {
+			if y > from {
+				ranges = append(ranges, &models.StateRange{BucketX: x, FromBucketY: from, ToBucketY: y - 1})
+			}
+			from = y + 1
+		}
+	}
+	return ranges
+}
+
+// Analyze computes analytics over the graph of states of jugs of capacities
+// xMax and yMax: how many states are reachable from the initial one, the
+// diameter of the graph, the eccentricity of the initial state, the targets
+// needing the most steps and the states that cannot be reached, as ranges.
+// Every reachable state leads back to the initial one by emptying both jugs,
+// so the diameter is the largest number of steps between any two of them.
+// Finding it takes a search from every reachable state, so pairs with more
+// than MaxGraphStates of them are rejected.
+func Analyze(xMax, yMax int) (*models.StateAnalytics, error) {
+	states, successors, err := reachableGraph(xMax, yMax)
+	if err != nil {
+		return nil, err
+	}
+	total := (xMax + 1) * (yMax + 1)
+	analytics := &models.StateAnalytics{
+		XCap:              xMax,
+		YCap:              yMax,
+		TotalStates:       total,
+		ReachableStates:   len(successors),
+		UnreachableStates: unreachableRanges(xMax, yMax, states),
+		HardestTargets:    []int{},
+	}
+	dist := make([]int, len(successors))
+	analytics.InitialEccentricity = maxDistance(successors, 0, dist)
+	analytics.Diameter = analytics.InitialEccentricity
+	for from := 1; from < len(successors); from++ {
+		analytics.Diameter = max(analytics.Diameter, maxDistance(successors, from, dist))
+	}
+	steps := MinSteps(xMax, yMax)
+	for target := 1; target <= max(xMax, yMax); target++ {
+		targetSteps, ok := steps[target]
+		switch {
+		case !ok || targetSteps < analytics.HardestTargetSteps:
+		case targetSteps > analytics.HardestTargetSteps:
+			analytics.HardestTargetSteps = targetSteps
+			analytics.HardestTargets = []int{target}
+		default:
+			analytics.HardestTargets = append(analytics.HardestTargets, target)
+		}
+	}
+	return analytics, nil
+}
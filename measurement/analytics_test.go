@@ -0,0 +1,170 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// eccentricity returns the largest number of steps from the given node
+// to any other node of the graph, walking its edges.
+func eccentricity(graph *models.Graph, from string) int {
+	dist := map[string]int{from: 0}
+	queue := []string{from}
+	var farthest int
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		farthest = max(farthest, dist[current])
+		for _, edge := range graph.Edges {
+			if _, ok := dist[edge.To]; edge.From == current && !ok {
+				dist[edge.To] = dist[current] + 1
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+	return farthest
+}
+
+// countStates returns the number of states in the given ranges.
+func countStates(ranges []*models.StateRange) int {
+	var count int
+	for _, r := range ranges {
+		count += r.ToBucketY - r.FromBucketY + 1
+	}
+	return count
+}
+
+func TestAnalyze(t *testing.T) {
+	testCases := []struct {
+		name           string
+		xMax           int
+		yMax           int
+		expectedOutput *models.StateAnalytics
+		expectedError  error
+	}{
+		{
+			name: "happy path",
+			xMax: 2,
+			yMax: 4,
+			expectedOutput: &models.StateAnalytics{
+				XCap:                2,
+				YCap:                4,
+				TotalStates:         15,
+				ReachableStates:     6,
+				Diameter:            2,
+				InitialEccentricity: 2,
+				HardestTargetSteps:  1,
+				HardestTargets:      []int{2, 4},
+				UnreachableStates: []*models.StateRange{
+					{BucketX: 0, FromBucketY: 1, ToBucketY: 1},
+					{BucketX: 0, FromBucketY: 3, ToBucketY: 3},
+					{BucketX: 1, FromBucketY: 0, ToBucketY: 4},
+					{BucketX: 2, FromBucketY: 1, ToBucketY: 1},
+					{BucketX: 2, FromBucketY: 3, ToBucketY: 3},
+				},
+			},
+		},
+		{
+			name: "every state reachable",
+			xMax: 1,
+			yMax: 1,
+			expectedOutput: &models.StateAnalytics{
+				XCap:                1,
+				YCap:                1,
+				TotalStates:         4,
+				ReachableStates:     4,
+				Diameter:            2,
+				InitialEccentricity: 2,
+				HardestTargetSteps:  1,
+				HardestTargets:      []int{1},
+				UnreachableStates:   []*models.StateRange{},
+			},
+		},
+		{
+			name:          "too many reachable states",
+			xMax:          999,
+			yMax:          1000,
+			expectedError: ErrGraphTooLarge,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := Analyze(tc.xMax, tc.yMax)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error to occur, got "%v"`, err)
+				}
+				require.ErrorIs(t, err, tc.expectedError)
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error to occur, got nil`)
+				}
+				require.Equal(t, tc.expectedOutput, output)
+			}
+		})
+	}
+}
+
+func TestAnalyzeLargePair(t *testing.T) {
+	output, err := Analyze(4000, 6000)
+	require.NoError(t, err)
+	require.Equal(t, 24_010_001, output.TotalStates)
+	require.Equal(t, 10, output.ReachableStates)
+	require.Equal(t, 4, output.Diameter)
+	require.Equal(t, []int{2000}, output.HardestTargets)
+	require.Equal(t, 24_009_991, countStates(output.UnreachableStates))
+	// each amount of water in jug X has a single range, except for the
+	// empty and full ones, split by the reachable amounts in jug Y.
+	require.Len(t, output.UnreachableStates, 3999+2*3)
+}
+
+func TestAnalyzeMatchesStateGraph(t *testing.T) {
+	for xMax := 1; xMax <= 9; xMax++ {
+		for yMax := 1; yMax <= 9; yMax++ {
+			output, err := Analyze(xMax, yMax)
+			require.NoError(t, err)
+			graph, err := StateGraph(xMax, yMax)
+			require.NoError(t, err)
+			require.Lenf(t, graph.Nodes, output.ReachableStates, "x=%d y=%d", xMax, yMax)
+			require.Equalf(t, output.TotalStates, output.ReachableStates+countStates(output.UnreachableStates), "x=%d y=%d", xMax, yMax)
+			reachable := make(map[string]bool)
+			for _, node := range graph.Nodes {
+				reachable[node.ID] = true
+			}
+			for _, r := range output.UnreachableStates {
+				for y := r.FromBucketY; y <= r.ToBucketY; y++ {
+					require.Falsef(t, reachable[nodeID(r.BucketX, y)], "x=%d y=%d state=(%d,%d)", xMax, yMax, r.BucketX, y)
+				}
+			}
+			var diameter int
+			for _, node := range graph.Nodes {
+				diameter = max(diameter, eccentricity(graph, node.ID))
+			}
+			require.Equalf(t, diameter, output.Diameter, "x=%d y=%d", xMax, yMax)
+			require.Equalf(t, eccentricity(graph, nodeID(0, 0)), output.InitialEccentricity, "x=%d y=%d", xMax, yMax)
+			steps := MinSteps(xMax, yMax)
+			for target, targetSteps := range steps {
+				require.LessOrEqualf(t, targetSteps, output.HardestTargetSteps, "x=%d y=%d z=%d", xMax, yMax, target)
+			}
+			for _, target := range output.HardestTargets {
+				require.Equalf(t, output.HardestTargetSteps, steps[target], "x=%d y=%d z=%d", xMax, yMax, target)
+			}
+		}
+	}
+}
+
+// BenchmarkAnalyze measures the worst case allowed by MaxGraphStates,
+// where the diameter search visits every reachable state from each of them.
+func BenchmarkAnalyze(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := Analyze(1, 999); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
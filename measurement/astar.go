@@ -0,0 +1,193 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"container/heap"
+
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// heuristic estimates the number of actions remaining to reach a goal state.
+// It must never overestimate for A* to return optimal solutions.
+type heuristic func(s *state, p Problem) int
+
+// zeroHeuristic never estimates any remaining action, turning A* into
+// a uniform-cost search.
+func zeroHeuristic(s *state, p Problem) int {
+	return 0
+}
+
+// node is an entry of the A* open list.
+type node struct {
+	s     *state
+	g     int // g is the number of actions taken so far.
+	f     int // f is g plus the heuristic estimate.
+	order int // order breaks ties deterministically, in insertion order.
+}
+
+// openList is a priority queue of nodes ordered by their f value.
+type openList []*node
+
+func (o openList) Len() int { return len(o) }
+
+func (o openList) Less(i, j int) bool {
+	if o[i].f != o[j].f {
+		return o[i].f < o[j].f
+	}
+	return o[i].order < o[j].order
+}
+
+func (o openList) Swap(i, j int) { o[i], o[j] = o[j], o[i] }
+
+func (o *openList) Push(x any) { *o = append(*o, x.(*node)) }
+
+func (o *openList) Pop() any {
+	old := *o
+	n := old[len(old)-1]
+	*o = old[:len(old)-1]
+	return n
+}
+
+// aStar is a solver that performs an A* search guided by a heuristic.
+type aStar struct {
+	heuristic heuristic
+}
+
+// search performs the A* search, returning the final state of the shortest path.
+func (a *aStar) search(p Problem, stats *searchStats) *state {
+	if !p.feasible() {
+		return nil
+	}
+	start := initialState()
+	best := map[[2]int]int{{0, 0}: 0}
+	closed := make(map[[2]int]bool)
+	open := &openList{{s: start, f: a.heuristic(start, p)}}
+	stats.visit()
+	stats.frontier(open.Len())
+	var order int
+	for open.Len() > 0 {
+		current := heap.Pop(open).(*node)
+		key := [2]int{current.s.x, current.s.y}
+		if closed[key] {
+			continue
+		}
+		closed[key] = true
+		if current.s.prev != nil && p.reached(current.s) {
+			current.s.status = "Solved"
+			return current.s
+		}
+		stats.expand()
+		for _, nextState := range transitions(current.s, p.XCap, p.YCap) {
+			nextKey := [2]int{nextState.x, nextState.y}
+			g := current.g + 1
+			if closed[nextKey] {
+				continue
+			}
+			known, ok := best[nextKey]
+			if ok && known <= g {
+				continue
+			}
+			if !ok {
+				stats.visit()
+			}
+			best[nextKey] = g
+			order++
+			heap.Push(open, &node{
+				s:     nextState,
+				g:     g,
+				f:     g + a.heuristic(nextState, p),
+				order: order,
+			})
+		}
+		stats.frontier(open.Len())
+	}
+	return nil
+}
+
+// Solve calculates the solution to the given problem using A* search.
+func (a *aStar) Solve(p Problem) (*models.Solution, error) {
+	return a.solveWithStats(p, nil)
+}
+
+// solveWithStats calculates the solution to the given problem using A* search,
+// collecting statistics about it.
+func (a *aStar) solveWithStats(p Problem, stats *searchStats) (*models.Solution, error) {
+	if p.Accumulate {
+		return nil, ErrUnsupportedGoal
+	}
+	if len(p.ActionOrder) > 0 {
+		return nil, ErrUnsupportedActionOrder
+	}
+	return solutionFrom(a.search(p, stats)), nil
+}
+
+// bezoutHeuristic is an admissible and consistent heuristic made of two
+// lower bounds on the number of remaining actions:
+//
+//   - By Bézout's identity, every amount that can be measured is a multiple of
+//     gcd(xMax, yMax). Since every action leaves at least one jug empty or full,
+//     a goal state holds one of a few possible totals of water. Only filling and
+//     emptying change the total, each by at most max(xMax, yMax), which bounds
+//     the number of actions needed to bring the current total to a goal total.
+//   - A state that is not a goal needs at least one more action, and at least
+//     two when none of its successors is a goal.
+//
+// For custom goals only the second bound applies.
+func bezoutHeuristic(s *state, p Problem) int {
+	if p.reached(s) {
+		return 0
+	}
+	lookahead := 2
+	for _, nextState := range transitions(s, p.XCap, p.YCap) {
+		if p.reached(nextState) {
+			lookahead = 1
+			break
+		}
+	}
+	if p.Goal != nil {
+		return lookahead
+	}
+	xMax, yMax, target := p.XCap, p.YCap, p.Target
+	g := gcd(xMax, yMax)
+	distance := -1
+	// nearest updates distance with the gap between the current total
+	// and the goal totals in [lo, hi] that are multiples of g.
+	nearest := func(lo, hi int) {
+		lo, hi = (lo+g-1)/g*g, hi/g*g
+		if lo > hi {
+			return
+		}
+		var d int
+		switch total := s.x + s.y; {
+		case total < lo:
+			d = lo - total
+		case total > hi:
+			d = total - hi
+		}
+		if distance < 0 || d < distance {
+			distance = d
+		}
+	}
+	// goalTotals feeds nearest with the totals of the goal states where a jug
+	// of capacity jugMax holds the target and the other one has capacity otherMax.
+	goalTotals := func(jugMax, otherMax int) {
+		switch {
+		case target > jugMax:
+		case target == jugMax:
+			nearest(target, target+otherMax)
+		default:
+			nearest(target, target)
+			nearest(target+otherMax, target+otherMax)
+		}
+	}
+	goalTotals(xMax, yMax)
+	goalTotals(yMax, xMax)
+	if distance < 0 {
+		return lookahead
+	}
+	maxCap := max(xMax, yMax)
+	return max(lookahead, (distance+maxCap-1)/maxCap)
+}
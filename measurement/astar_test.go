@@ -0,0 +1,92 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// distancesToGoal returns the exact number of actions needed to reach a goal
+// state from every state that can reach one, by searching backwards from the goals.
+func distancesToGoal(xMax, yMax, target int) map[[2]int]int {
+	distances := make(map[[2]int]int)
+	var queue [][2]int
+	for x := 0; x <= xMax; x++ {
+		for y := 0; y <= yMax; y++ {
+			if x == target || y == target {
+				distances[[2]int{x, y}] = 0
+				queue = append(queue, [2]int{x, y})
+			}
+		}
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		states, _ := predecessors(current, xMax, yMax)
+		for _, prev := range states {
+			if _, ok := distances[prev]; !ok {
+				distances[prev] = distances[current] + 1
+				queue = append(queue, prev)
+			}
+		}
+	}
+	return distances
+}
+
+func TestBezoutHeuristicIsAdmissible(t *testing.T) {
+	for xMax := 1; xMax <= 10; xMax++ {
+		for yMax := 1; yMax <= 10; yMax++ {
+			for target := 1; target <= max(xMax, yMax); target++ {
+				distances := distancesToGoal(xMax, yMax, target)
+				for key, distance := range distances {
+					if key == [2]int{0, 0} {
+						continue
+					}
+					h := bezoutHeuristic(&state{x: key[0], y: key[1]}, Problem{XCap: xMax, YCap: yMax, Target: target})
+					require.LessOrEqualf(t, h, distance, "x=%d y=%d z=%d state=%v", xMax, yMax, target, key)
+				}
+			}
+		}
+	}
+}
+
+func TestAStarExpandsNoMoreThanUniformCost(t *testing.T) {
+	var informed, uninformed searchStats
+	p := Problem{XCap: 997, YCap: 1009, Target: 500}
+	(&aStar{heuristic: bezoutHeuristic}).search(p, &informed)
+	(&aStar{heuristic: zeroHeuristic}).search(p, &uninformed)
+	require.LessOrEqual(t, informed.expanded, uninformed.expanded)
+}
+
+func BenchmarkExpandedNodes(b *testing.B) {
+	instances := [][3]int{
+		{2, 100, 96},
+		{997, 1009, 500},
+		{3001, 7919, 1234},
+		{9973, 9967, 5000},
+	}
+	searches := []struct {
+		name   string
+		search func(p Problem, stats *searchStats) *state
+	}{
+		{name: BFS, search: bfs},
+		{name: AStar, search: (&aStar{heuristic: bezoutHeuristic}).search},
+	}
+	for _, instance := range instances {
+		for _, s := range searches {
+			b.Run(fmt.Sprintf("%s/x=%d,y=%d,z=%d", s.name, instance[0], instance[1], instance[2]), func(b *testing.B) {
+				var stats searchStats
+				for i := 0; i < b.N; i++ {
+					stats = searchStats{}
+					s.search(Problem{XCap: instance[0], YCap: instance[1], Target: instance[2]}, &stats)
+				}
+				b.ReportMetric(float64(stats.expanded), "expanded/op")
+			})
+		}
+	}
+}
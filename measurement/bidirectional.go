@@ -0,0 +1,153 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import "github.com/tiagomelo/golang-waterjug-api/measurement/models"
+
+// backwardNode represents a state discovered by the reverse search,
+// along with the action that leads from it towards a goal state.
+type backwardNode struct {
+	next   [2]int
+	action string
+	goal   bool
+	depth  int
+}
+
+// predecessors returns the states from which a single action leads to s,
+// together with the action taken.
+func predecessors(s [2]int, xMax, yMax int) ([][2]int, []string) {
+	var candidates [][2]int
+	for x := 0; x <= xMax; x++ {
+		candidates = append(candidates, [2]int{x, s[1]})
+	}
+	for y := 0; y <= yMax; y++ {
+		candidates = append(candidates, [2]int{s[0], y})
+	}
+	total := s[0] + s[1]
+	for x := max(0, total-yMax); x <= min(xMax, total); x++ {
+		candidates = append(candidates, [2]int{x, total - x})
+	}
+	seen := make(map[[2]int]bool)
+	var (
+		states  [][2]int
+		actions []string
+	)
+	for _, c := range candidates {
+		if c == s || seen[c] {
+			continue
+		}
+		seen[c] = true
+		for _, nextState := range transitions(&state{x: c[0], y: c[1]}, xMax, yMax) {
+			if nextState.x == s[0] && nextState.y == s[1] {
+				states = append(states, c)
+				actions = append(actions, nextState.action)
+				break
+			}
+		}
+	}
+	return states, actions
+}
+
+// bidirectional performs a bidirectional breadth-first search, running a
+// forward search from the initial state and a reverse search from every goal
+// state until they meet. Both searches expand a full layer at a time so that
+// the shortest path through the meeting point is found.
+func bidirectional(p Problem, stats *searchStats) *state {
+	if !p.feasible() {
+		return nil
+	}
+	xMax, yMax := p.XCap, p.YCap
+	start := initialState()
+	forward := map[[2]int]*state{{0, 0}: start}
+	forwardDepth := map[[2]int]int{{0, 0}: 0}
+	forwardFrontier := []*state{start}
+	backward := make(map[[2]int]*backwardNode)
+	backwardFrontier := p.goalStates()
+	stats.visit()
+	for _, key := range backwardFrontier {
+		backward[key] = &backwardNode{goal: true}
+		stats.visit()
+	}
+	for len(forwardFrontier) > 0 && len(backwardFrontier) > 0 {
+		stats.frontier(len(forwardFrontier) + len(backwardFrontier))
+		var (
+			meetFrom   *state
+			meetTo     [2]int
+			meetAction string
+			meetLength = -1
+		)
+		// meet records the shortest path found so far crossing from a
+		// forward state to a backward state through a single action.
+		meet := func(from *state, to [2]int, action string) {
+			length := forwardDepth[[2]int{from.x, from.y}] + 1 + backward[to].depth
+			if meetLength < 0 || length < meetLength {
+				meetFrom, meetTo, meetAction, meetLength = from, to, action, length
+			}
+		}
+		if len(forwardFrontier) <= len(backwardFrontier) {
+			var next []*state
+			for _, currentState := range forwardFrontier {
+				stats.expand()
+				for _, nextState := range transitions(currentState, xMax, yMax) {
+					key := [2]int{nextState.x, nextState.y}
+					if _, ok := backward[key]; ok {
+						meet(currentState, key, nextState.action)
+					}
+					if _, ok := forward[key]; !ok {
+						forward[key] = nextState
+						forwardDepth[key] = forwardDepth[[2]int{currentState.x, currentState.y}] + 1
+						stats.visit()
+						next = append(next, nextState)
+					}
+				}
+			}
+			forwardFrontier = next
+		} else {
+			var next [][2]int
+			for _, key := range backwardFrontier {
+				stats.expand()
+				states, actions := predecessors(key, xMax, yMax)
+				for i, prevKey := range states {
+					if prevState, ok := forward[prevKey]; ok {
+						meet(prevState, key, actions[i])
+					}
+					if _, ok := backward[prevKey]; !ok {
+						backward[prevKey] = &backwardNode{next: key, action: actions[i], depth: backward[key].depth + 1}
+						stats.visit()
+						next = append(next, prevKey)
+					}
+				}
+			}
+			backwardFrontier = next
+		}
+		if meetLength >= 0 {
+			return joinPaths(meetFrom, meetTo, meetAction, backward)
+		}
+	}
+	return nil
+}
+
+// joinPaths concatenates the forward path ending at from with the reverse
+// path starting at to, returning the final state of the complete path.
+func joinPaths(from *state, to [2]int, action string, backward map[[2]int]*backwardNode) *state {
+	current := &state{x: to[0], y: to[1], action: action, prev: from}
+	for node := backward[to]; !node.goal; node = backward[node.next] {
+		current = &state{x: node.next[0], y: node.next[1], action: node.action, prev: current}
+	}
+	current.status = "Solved"
+	return current
+}
+
+// measureBidirectional calculates the solution to the given problem
+// using bidirectional search.
+func measureBidirectional(p Problem, stats *searchStats) (*models.Solution, error) {
+	if p.Accumulate {
+		return nil, ErrUnsupportedGoal
+	}
+	if len(p.ActionOrder) > 0 {
+		return nil, ErrUnsupportedActionOrder
+	}
+	return solutionFrom(bidirectional(p, stats)), nil
+}
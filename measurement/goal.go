@@ -0,0 +1,59 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"errors"
+
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// ErrEmptyGoal is returned when a goal specification has no condition.
+var ErrEmptyGoal = errors.New("goal must specify at least one condition")
+
+// Goal is a compiled goal specification: it reports whether
+// the amounts x and y in the jugs satisfy the goal.
+type Goal func(x, y int) bool
+
+// CompileGoal compiles a goal specification into a predicate.
+// Every condition in the specification must hold for the goal to be reached.
+// A nil specification compiles to a nil Goal, meaning the default goal
+// of either jug holding the target amount.
+func CompileGoal(spec *models.Goal) (Goal, error) {
+	if spec == nil {
+		return nil, nil
+	}
+	var conditions []Goal
+	if spec.X != nil {
+		xWanted := *spec.X
+		conditions = append(conditions, func(x, y int) bool { return x == xWanted })
+	}
+	if spec.Y != nil {
+		yWanted := *spec.Y
+		conditions = append(conditions, func(x, y int) bool { return y == yWanted })
+	}
+	if len(spec.AnyOf) > 0 {
+		amounts := make(map[int]bool, len(spec.AnyOf))
+		for _, amount := range spec.AnyOf {
+			amounts[amount] = true
+		}
+		conditions = append(conditions, func(x, y int) bool { return amounts[x] || amounts[y] })
+	}
+	if spec.Difference != nil {
+		difference := *spec.Difference
+		conditions = append(conditions, func(x, y int) bool { return x-y == difference })
+	}
+	if len(conditions) == 0 {
+		return nil, ErrEmptyGoal
+	}
+	return func(x, y int) bool {
+		for _, condition := range conditions {
+			if !condition(x, y) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
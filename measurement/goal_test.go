@@ -0,0 +1,142 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestCompileGoal(t *testing.T) {
+	testCases := []struct {
+		name          string
+		spec          *models.Goal
+		reached       [][2]int
+		notReached    [][2]int
+		expectedError error
+	}{
+		{
+			name: "no goal",
+		},
+		{
+			name:       "exact pair",
+			spec:       &models.Goal{X: intPtr(4), Y: intPtr(0)},
+			reached:    [][2]int{{4, 0}},
+			notReached: [][2]int{{4, 1}, {0, 4}},
+		},
+		{
+			name:       "any of",
+			spec:       &models.Goal{AnyOf: []int{2, 7}},
+			reached:    [][2]int{{2, 0}, {3, 7}},
+			notReached: [][2]int{{3, 0}},
+		},
+		{
+			name:       "difference",
+			spec:       &models.Goal{Difference: intPtr(1)},
+			reached:    [][2]int{{4, 3}, {1, 0}},
+			notReached: [][2]int{{3, 4}},
+		},
+		{
+			name:          "empty goal",
+			spec:          &models.Goal{},
+			expectedError: ErrEmptyGoal,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			goal, err := CompileGoal(tc.spec)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error to occur, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error to occur, got nil`)
+				}
+				if tc.spec == nil {
+					require.Nil(t, goal)
+				}
+				for _, s := range tc.reached {
+					require.Truef(t, goal(s[0], s[1]), "%v", s)
+				}
+				for _, s := range tc.notReached {
+					require.Falsef(t, goal(s[0], s[1]), "%v", s)
+				}
+			}
+		})
+	}
+}
+
+func TestCustomGoals(t *testing.T) {
+	testCases := []struct {
+		name          string
+		spec          *models.Goal
+		expectedSteps int
+	}{
+		{
+			name:          "exact pair",
+			spec:          &models.Goal{X: intPtr(4), Y: intPtr(0)},
+			expectedSteps: 7,
+		},
+		{
+			name:          "any of",
+			spec:          &models.Goal{AnyOf: []int{2, 7}},
+			expectedSteps: 2,
+		},
+		{
+			name:          "difference",
+			spec:          &models.Goal{Difference: intPtr(1)},
+			expectedSteps: 6,
+		},
+		{
+			name:          "satisfied by empty jugs",
+			spec:          &models.Goal{Difference: intPtr(0)},
+			expectedSteps: 3,
+		},
+		{
+			name: "unreachable",
+			spec: &models.Goal{X: intPtr(1), Y: intPtr(1)},
+		},
+		{
+			name: "empty jugs only",
+			spec: &models.Goal{X: intPtr(0), Y: intPtr(0)},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := NewProblem(&models.NewMeasurement{XCap: 5, YCap: 3, Goal: tc.spec})
+			require.NoError(t, err)
+			for _, strategy := range []string{BFS, Bidirectional, AStar} {
+				output, err := MeasureWith(strategy, p)
+				if tc.expectedSteps == 0 {
+					require.Equal(t, &NoSolutionError{Reason: ReasonConstraintViolation, XCap: 5, YCap: 3}, err, strategy)
+					require.Nil(t, output, strategy)
+					continue
+				}
+				require.NoError(t, err)
+				require.NotNil(t, output, strategy)
+				require.Len(t, output.Steps, tc.expectedSteps, strategy)
+				requireValidSolution(t, p, output)
+			}
+			output, err := BuildTree(5, 3).Solve(p)
+			if tc.expectedSteps == 0 {
+				require.Equal(t, &NoSolutionError{Reason: ReasonConstraintViolation, XCap: 5, YCap: 3}, err)
+			} else {
+				require.NoError(t, err)
+				require.Len(t, output.Steps, tc.expectedSteps)
+				requireValidSolution(t, p, output)
+			}
+			_, err = MeasureWith(Math, p)
+			require.ErrorIs(t, err, ErrUnsupportedGoal)
+		})
+	}
+}
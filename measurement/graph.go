@@ -0,0 +1,92 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// MaxGraphStates is the maximum number of states a state graph can hold.
+const MaxGraphStates = 2000
+
+// ErrGraphTooLarge is returned when the state graph exceeds MaxGraphStates.
+var ErrGraphTooLarge = errors.New("state graph too large")
+
+// nodeID returns the identifier of the node representing the amounts x and y.
+func nodeID(x, y int) string {
+	return fmt.Sprintf("%d,%d", x, y)
+}
+
+// StateGraph builds the graph of every state reachable from the initial one
+// with jugs of capacities xMax and yMax. Each action that changes the state
+// is an edge labeled with the action taken.
+func StateGraph(xMax, yMax int) (*models.Graph, error) {
+	graph := &models.Graph{XCap: xMax, YCap: yMax}
+	start := initialState()
+	visited := map[[2]int]bool{{0, 0}: true}
+	graph.Nodes = append(graph.Nodes, &models.GraphNode{ID: nodeID(0, 0), Initial: true})
+	queue := []*state{start}
+	for len(queue) > 0 {
+		currentState := queue[0]
+		queue = queue[1:]
+		for _, nextState := range transitions(currentState, xMax, yMax) {
+			if nextState.x == currentState.x && nextState.y == currentState.y {
+				continue
+			}
+			key := [2]int{nextState.x, nextState.y}
+			if !visited[key] {
+				if len(graph.Nodes) == MaxGraphStates {
+					return nil, fmt.Errorf("%w: more than %d reachable states", ErrGraphTooLarge, MaxGraphStates)
+				}
+				visited[key] = true
+				graph.Nodes = append(graph.Nodes, &models.GraphNode{
+					ID:      nodeID(nextState.x, nextState.y),
+					BucketX: nextState.x,
+					BucketY: nextState.y,
+				})
+				queue = append(queue, nextState)
+			}
+			graph.Edges = append(graph.Edges, &models.GraphEdge{
+				From:   nodeID(currentState.x, currentState.y),
+				To:     nodeID(nextState.x, nextState.y),
+				Action: nextState.action,
+			})
+		}
+	}
+	return graph, nil
+}
+
+// HighlightSolution marks the nodes and edges of the graph that are
+// traversed by the given solution.
+func HighlightSolution(graph *models.Graph, solution *models.Solution) {
+	if solution == nil {
+		return
+	}
+	nodes := make(map[string]*models.GraphNode, len(graph.Nodes))
+	for _, node := range graph.Nodes {
+		nodes[node.ID] = node
+	}
+	edges := make(map[[3]string]*models.GraphEdge, len(graph.Edges))
+	for _, edge := range graph.Edges {
+		edges[[3]string{edge.From, edge.To, edge.Action}] = edge
+	}
+	from := nodeID(0, 0)
+	if node, ok := nodes[from]; ok {
+		node.OnPath = true
+	}
+	for _, step := range solution.Steps {
+		to := nodeID(step.BucketX, step.BucketY)
+		if node, ok := nodes[to]; ok {
+			node.OnPath = true
+		}
+		if edge, ok := edges[[3]string{from, to, step.Action}]; ok {
+			edge.OnPath = true
+		}
+		from = to
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+func TestStateGraph(t *testing.T) {
+	testCases := []struct {
+		name          string
+		xMax          int
+		yMax          int
+		expectedNodes int
+		expectedEdges int
+		expectedError error
+	}{
+		{
+			name:          "small graph",
+			xMax:          1,
+			yMax:          1,
+			expectedNodes: 4,
+			expectedEdges: 10,
+		},
+		{
+			name:          "reachable states only",
+			xMax:          2,
+			yMax:          4,
+			expectedNodes: 6,
+		},
+		{
+			name:          "graph too large",
+			xMax:          9999,
+			yMax:          9998,
+			expectedError: ErrGraphTooLarge,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := StateGraph(tc.xMax, tc.yMax)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error to occur, got "%v"`, err)
+				}
+				require.ErrorIs(t, err, tc.expectedError)
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error to occur, got nil`)
+				}
+				require.Len(t, output.Nodes, tc.expectedNodes)
+				if tc.expectedEdges > 0 {
+					require.Len(t, output.Edges, tc.expectedEdges)
+				}
+				require.True(t, output.Nodes[0].Initial)
+			}
+		})
+	}
+}
+
+func TestHighlightSolution(t *testing.T) {
+	graph, err := StateGraph(2, 100)
+	require.NoError(t, err)
+	solution, err := Measure(2, 100, 96)
+	require.NoError(t, err)
+	HighlightSolution(graph, solution)
+	var nodes, edges []string
+	for _, node := range graph.Nodes {
+		if node.OnPath {
+			nodes = append(nodes, node.ID)
+		}
+	}
+	for _, edge := range graph.Edges {
+		if edge.OnPath {
+			edges = append(edges, edge.From+"->"+edge.To)
+		}
+	}
+	require.Equal(t, []string{"0,0", "0,100", "2,98", "0,98", "2,96"}, nodes)
+	require.Equal(t, []string{"0,0->0,100", "0,100->2,98", "2,98->0,98", "0,98->2,96"}, edges)
+	HighlightSolution(&models.Graph{}, nil)
+}
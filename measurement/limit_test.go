@@ -0,0 +1,85 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+func TestMaxSteps(t *testing.T) {
+	six := 6
+	testCases := []struct {
+		name              string
+		strategy          string
+		problem           Problem
+		expectedSteps     int
+		expectedStepLimit *models.StepLimit
+		expectedError     error
+	}{
+		{
+			name:              "optimal solution fits",
+			strategy:          BFS,
+			problem:           Problem{XCap: 3, YCap: 5, Target: 4, MaxSteps: 6},
+			expectedSteps:     6,
+			expectedStepLimit: &models.StepLimit{MaxSteps: 6, Fits: true, Optimum: &six},
+		},
+		{
+			name:          "bfs stops at the limit",
+			strategy:      BFS,
+			problem:       Problem{XCap: 3, YCap: 5, Target: 4, MaxSteps: 5},
+			expectedError: &NoSolutionError{Reason: ReasonBudgetExhausted, XCap: 3, YCap: 5, Target: 4, MaxSteps: 5},
+		},
+		{
+			name:          "other strategies search to the end",
+			strategy:      AStar,
+			problem:       Problem{XCap: 3, YCap: 5, Target: 4, MaxSteps: 5},
+			expectedError: &NoSolutionError{Reason: ReasonBudgetExhausted, XCap: 3, YCap: 5, Target: 4, MaxSteps: 5, Optimum: &six},
+		},
+		{
+			name:          "unreachable custom goal",
+			strategy:      BFS,
+			problem:       Problem{XCap: 5, YCap: 3, Goal: func(x, y int) bool { return x == 1 && y == 1 }, MaxSteps: 5},
+			expectedError: &NoSolutionError{Reason: ReasonBudgetExhausted, XCap: 5, YCap: 3, MaxSteps: 5},
+		},
+		{
+			name:          "accumulation",
+			strategy:      BFS,
+			problem:       Problem{XCap: 3, YCap: 5, Target: 13, Accumulate: true, MaxSteps: 5},
+			expectedError: &NoSolutionError{Reason: ReasonBudgetExhausted, XCap: 3, YCap: 5, Target: 13, MaxSteps: 5},
+		},
+		{
+			name:          "no solution at all",
+			strategy:      BFS,
+			problem:       Problem{XCap: 2, YCap: 4, Target: 3, MaxSteps: 5},
+			expectedError: &NoSolutionError{Reason: ReasonGCDMismatch, XCap: 2, YCap: 4, Target: 3, GCD: 2},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := MeasureWith(tc.strategy, tc.problem)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error to occur, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError, err)
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error to occur, got nil`)
+				}
+				require.Len(t, output.Steps, tc.expectedSteps)
+				require.Equal(t, tc.expectedStepLimit, output.StepLimit)
+			}
+		})
+	}
+}
+
+func TestTreeMaxSteps(t *testing.T) {
+	_, err := BuildTree(3, 5).Solve(Problem{XCap: 3, YCap: 5, Target: 4, MaxSteps: 5})
+	six := 6
+	require.Equal(t, &NoSolutionError{Reason: ReasonBudgetExhausted, XCap: 3, YCap: 5, Target: 4, MaxSteps: 5, Optimum: &six}, err)
+}
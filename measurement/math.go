@@ -0,0 +1,89 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import "github.com/tiagomelo/golang-waterjug-api/measurement/models"
+
+// pour simulates the closed-form procedure that always pours from one jug
+// into the other: whenever the source jug is empty it is filled, whenever
+// the destination jug is full it is emptied, and otherwise the source is
+// transferred into the destination. When xToY is false, jug Y is the source.
+func pour(xMax, yMax, target int, xToY bool, stats *searchStats) *state {
+	fill, empty, transfer := "Fill bucket X", "Empty bucket Y", "Transfer from bucket X to Y"
+	srcMax, dstMax := xMax, yMax
+	if !xToY {
+		fill, empty, transfer = "Fill bucket Y", "Empty bucket X", "Transfer from bucket Y to X"
+		srcMax, dstMax = yMax, xMax
+	}
+	current := initialState()
+	src, dst := 0, 0
+	visited := map[[2]int]bool{{0, 0}: true}
+	stats.visit()
+	stats.frontier(1)
+	for {
+		stats.expand()
+		var action string
+		switch {
+		case src == 0:
+			src, action = srcMax, fill
+		case dst == dstMax:
+			dst, action = 0, empty
+		default:
+			amount := min(src, dstMax-dst)
+			src, dst, action = src-amount, dst+amount, transfer
+		}
+		x, y := src, dst
+		if !xToY {
+			x, y = dst, src
+		}
+		current = &state{x: x, y: y, action: action, prev: current}
+		if isGoal(current, target) {
+			current.status = "Solved"
+			return current
+		}
+		if visited[[2]int{src, dst}] {
+			// the procedure is cycling: the target is unreachable.
+			return nil
+		}
+		visited[[2]int{src, dst}] = true
+		stats.visit()
+	}
+}
+
+// pathLength returns the number of actions taken to reach s.
+func pathLength(s *state) int {
+	var n int
+	for ; s.prev != nil; s = s.prev {
+		n++
+	}
+	return n
+}
+
+// measureMath calculates the solution to the water jug problem without
+// searching the state space. It simulates both pouring procedures,
+// from X into Y and from Y into X, and picks the shorter one.
+// Only the default goal of either jug holding the target is supported.
+func measureMath(p Problem, stats *searchStats) (*models.Solution, error) {
+	if p.Goal != nil || p.Accumulate {
+		return nil, ErrUnsupportedGoal
+	}
+	if len(p.ActionOrder) > 0 {
+		return nil, ErrUnsupportedActionOrder
+	}
+	if !solvable(p.XCap, p.YCap, p.Target) {
+		return nil, nil
+	}
+	xToY := pour(p.XCap, p.YCap, p.Target, true, stats)
+	yToX := pour(p.XCap, p.YCap, p.Target, false, stats)
+	switch {
+	case xToY == nil:
+		return solutionFrom(yToX), nil
+	case yToX == nil:
+		return solutionFrom(xToY), nil
+	case pathLength(yToX) < pathLength(xToY):
+		return solutionFrom(yToX), nil
+	}
+	return solutionFrom(xToY), nil
+}
@@ -8,11 +8,13 @@ import "github.com/tiagomelo/golang-waterjug-api/measurement/models"
 
 // state represents the state of the water jugs, including their amounts and the previous state.
 type state struct {
-	x      int
-	y      int
-	action string
-	status string
-	prev   *state
+	x        int
+	y        int
+	receiver int // receiver is the amount in the receiving vessel, in accumulation mode.
+	depth    int // depth is the number of steps taken to reach the state.
+	action   string
+	status   string
+	prev     *state
 }
 
 // gcd computes the greatest common divisor (GCD) of two integers, a and b.
@@ -27,66 +29,147 @@ func gcd(a, b int) int {
 	return a
 }
 
+// solvable reports whether the target amount can be measured with jugs of
+// capacities xMax and yMax, according to the theory of Diophantine equations:
+// it must fit in one of the jugs and be a multiple of gcd(xMax, yMax).
+func solvable(xMax, yMax, target int) bool {
+	return target <= max(xMax, yMax) && target%gcd(xMax, yMax) == 0
+}
+
+// actions lists the six actions, in the order the solvers try them.
+var actions = []string{
+	"Fill bucket X",
+	"Fill bucket Y",
+	"Empty bucket X",
+	"Empty bucket Y",
+	"Transfer from bucket X to Y",
+	"Transfer from bucket Y to X",
+}
+
+// move returns the amounts in the jugs after applying the action at the
+// given index of actions to jugs holding x and y.
+func move(x, y, xMax, yMax, action int) (int, int) {
+	switch action {
+	case 0:
+		return xMax, y
+	case 1:
+		return x, yMax
+	case 2:
+		return 0, y
+	case 3:
+		return x, 0
+	case 4:
+		return x - min(x, yMax-y), y + min(x, yMax-y)
+	default:
+		return x + min(y, xMax-x), y - min(y, xMax-x)
+	}
+}
+
+// transitions returns the six states reachable from the current state
+// in a single action: filling, emptying or transferring between jugs.
+func transitions(currentState *state, xMax, yMax int) []*state {
+	nextStates := make([]*state, len(actions))
+	for i, action := range actions {
+		x, y := move(currentState.x, currentState.y, xMax, yMax, i)
+		nextStates[i] = &state{x: x, y: y, receiver: currentState.receiver, depth: currentState.depth + 1, action: action, prev: currentState}
+	}
+	return nextStates
+}
+
+// isGoal reports whether either jug holds exactly the target amount.
+func isGoal(s *state, target int) bool {
+	return s.x == target || s.y == target
+}
+
+// initialState returns the starting state, where both jugs are empty.
+func initialState() *state {
+	return &state{x: 0, y: 0, action: "Start"}
+}
+
 // bfs performs a breadth-first search (BFS) to find the minimum steps required
-// to measure exactly the target amount of water using two jugs with capacities xMax and yMax.
-func bfs(xMax, yMax, target int) *state {
-	if gcd(xMax, yMax) != gcd(xMax, target) {
+// to reach the problem's goal using two jugs with capacities p.XCap and p.YCap.
+func bfs(p Problem, stats *searchStats) *state {
+	return bfsFrom(p, initialState(), stats)
+}
+
+// bfsFrom performs a breadth-first search like bfs, starting from the given
+// state instead of empty jugs.
+func bfsFrom(p Problem, start *state, stats *searchStats) *state {
+	if !p.feasible() {
 		return nil
 	}
 	visited := make(map[[2]int]bool)
-	queue := []*state{{x: 0,
-		y:      0,
-		action: "Start",
-		prev:   nil,
-	}}
-	visited[[2]int{0, 0}] = true
+	queue := []*state{start}
+	visited[[2]int{start.x, start.y}] = true
+	stats.discover(queue[0])
+	stats.frontier(len(queue))
 	for len(queue) > 0 {
 		currentState := queue[0]
 		queue = queue[1:]
-		possibleStates := []*state{
-			{x: xMax, y: currentState.y, action: "Fill bucket X", prev: currentState},
-			{x: currentState.x, y: yMax, action: "Fill bucket Y", prev: currentState},
-			{x: 0, y: currentState.y, action: "Empty bucket X", prev: currentState},
-			{x: currentState.x, y: 0, action: "Empty bucket Y", prev: currentState},
-			{x: currentState.x - min(currentState.x, yMax-currentState.y), y: currentState.y + min(currentState.x, yMax-currentState.y), action: "Transfer from bucket X to Y", prev: currentState},
-			{x: currentState.x + min(currentState.y, xMax-currentState.x), y: currentState.y - min(currentState.y, xMax-currentState.x), action: "Transfer from bucket Y to X", prev: currentState},
-		}
-		for _, nextState := range possibleStates {
-			if nextState.x == target || nextState.y == target {
+		stats.expand()
+		for _, nextState := range p.prioritize(transitions(currentState, p.XCap, p.YCap)) {
+			if p.exceedsMaxSteps(nextState) {
+				return nil // every state within the limit was checked.
+			}
+			if p.reached(nextState) {
+				if !visited[[2]int{nextState.x, nextState.y}] {
+					stats.discover(nextState)
+				}
 				nextState.status = "Solved"
 				return nextState // found the solution.
 			}
 			if !visited[[2]int{nextState.x, nextState.y}] {
 				visited[[2]int{nextState.x, nextState.y}] = true
+				stats.discover(nextState)
 				queue = append(queue, nextState)
 			}
 		}
+		stats.frontier(len(queue))
 	}
 	return nil
 }
 
-// Measure calculates the solution to the water jug problem.
-func Measure(xMax, yMax, target int) *models.Solution {
-	s := bfs(xMax, yMax, target)
-	if s != nil {
-		solution := &models.Solution{}
-		states := []*state{}
-		for stateStep := s; stateStep != nil; stateStep = stateStep.prev {
-			states = append(states, stateStep)
+// solutionFrom walks back from the final state to the initial one
+// and converts the path into a solution.
+func solutionFrom(s *state) *models.Solution {
+	if s == nil {
+		return nil
+	}
+	solution := &models.Solution{}
+	states := []*state{}
+	for stateStep := s; stateStep != nil; stateStep = stateStep.prev {
+		states = append(states, stateStep)
+	}
+	var stepNumber int
+	for i := len(states) - 2; i >= 0; i-- { // Start from len(steps) - 2 to skip the initial state
+		stepNumber++
+		step := &models.Step{
+			Number:  stepNumber,
+			BucketX: states[i].x,
+			BucketY: states[i].y,
+			Action:  states[i].action,
+			Status:  states[i].status,
 		}
-		var stepNumber int
-		for i := len(states) - 2; i >= 0; i-- { // Start from len(steps) - 2 to skip the initial state
-			stepNumber++
-			step := &models.Step{
-				Number:  stepNumber,
-				BucketX: states[i].x,
-				BucketY: states[i].y,
-				Action:  states[i].action,
-				Status:  states[i].status,
-			}
-			solution.Steps = append(solution.Steps, step)
+		solution.Steps = append(solution.Steps, step)
+	}
+	return solution
+}
+
+// Measure calculates the solution to the water jug problem. When there's
+// none, the returned *NoSolutionError explains why.
+func Measure(xMax, yMax, target int) (*models.Solution, error) {
+	p := Problem{XCap: xMax, YCap: yMax, Target: target}
+	return p.settle(solutionFrom(bfs(p, nil)), true)
+}
+
+// measureBFS calculates the solution to the given problem using breadth-first search.
+func measureBFS(p Problem, stats *searchStats) (*models.Solution, error) {
+	if p.Accumulate {
+		s, err := accumulationBFS(p, stats)
+		if err != nil {
+			return nil, err
 		}
-		return solution
+		return accumulationSolutionFrom(s), nil
 	}
-	return nil
+	return solutionFrom(bfs(p, stats)), nil
 }
@@ -18,6 +18,7 @@ func TestMeasure(t *testing.T) {
 		yMax           int
 		target         int
 		expectedOutput *models.Solution
+		expectedError  error
 	}{
 		{
 			name:   "measurement is possible",
@@ -55,16 +56,52 @@ func TestMeasure(t *testing.T) {
 			},
 		},
 		{
-			name:   "different gcd",
-			xMax:   8,
-			yMax:   12,
-			target: 5,
+			name:   "multiple of the gcd of the capacities",
+			xMax:   4,
+			yMax:   6,
+			target: 4,
+			expectedOutput: &models.Solution{
+				Steps: []*models.Step{
+					{
+						Number:  1,
+						BucketX: 4,
+						BucketY: 0,
+						Action:  "Fill bucket X",
+						Status:  "Solved",
+					},
+				},
+			},
+		},
+		{
+			name:          "different gcd",
+			xMax:          8,
+			yMax:          12,
+			target:        5,
+			expectedError: &NoSolutionError{Reason: ReasonGCDMismatch, XCap: 8, YCap: 12, Target: 5, GCD: 4},
+		},
+		{
+			name:          "target exceeds capacities",
+			xMax:          3,
+			yMax:          5,
+			target:        8,
+			expectedError: &NoSolutionError{Reason: ReasonTargetExceedsCapacity, XCap: 3, YCap: 5, Target: 8},
 		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			output := Measure(tc.xMax, tc.yMax, tc.target)
-			require.Equal(t, tc.expectedOutput, output)
+			output, err := Measure(tc.xMax, tc.yMax, tc.target)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error to occur, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError, err)
+				require.ErrorIs(t, err, ErrNoSolution)
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error to occur, got nil`)
+				}
+				require.Equal(t, tc.expectedOutput, output)
+			}
 		})
 	}
 }
@@ -0,0 +1,115 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"math"
+
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// MinSteps returns the minimal number of steps needed to measure every
+// target amount with jugs of capacities xMax and yMax, computed from a
+// single breadth-first search. Targets that cannot be measured are absent.
+// For every target, the result matches the length of the solution
+// returned by Measure.
+func MinSteps(xMax, yMax int) map[int]int {
+	steps := make(map[int]int)
+	record := func(amount, depth int) {
+		if amount == 0 || !solvable(xMax, yMax, amount) {
+			return
+		}
+		if _, ok := steps[amount]; !ok {
+			steps[amount] = depth
+		}
+	}
+	visited := map[[2]int]bool{{0, 0}: true}
+	layer := []*state{initialState()}
+	for depth := 1; len(layer) > 0; depth++ {
+		var next []*state
+		for _, currentState := range layer {
+			for _, nextState := range transitions(currentState, xMax, yMax) {
+				record(nextState.x, depth)
+				record(nextState.y, depth)
+				key := [2]int{nextState.x, nextState.y}
+				if !visited[key] {
+					visited[key] = true
+					next = append(next, nextState)
+				}
+			}
+		}
+		layer = next
+	}
+	return steps
+}
+
+// OptimalSolutions returns the number of distinct optimal solutions, that
+// is, sequences of actions of minimal length, measuring every target amount
+// with jugs of capacities xMax and yMax, computed from a single breadth-first
+// search. Targets that cannot be measured are absent. Counts stop growing
+// at math.MaxInt.
+func OptimalSolutions(xMax, yMax int) map[int]int {
+	solutions := make(map[int]int)
+	// depths holds the depth at which every target was first measured.
+	depths := make(map[int]int)
+	// ways holds the number of shortest sequences of actions reaching every
+	// state, and stateDepths the length of those sequences.
+	ways := map[[2]int]int{{0, 0}: 1}
+	stateDepths := map[[2]int]int{{0, 0}: 0}
+	layer := [][2]int{{0, 0}}
+	for depth := 1; len(layer) > 0; depth++ {
+		var next [][2]int
+		for _, current := range layer {
+			for action := range actions {
+				x, y := move(current[0], current[1], xMax, yMax, action)
+				key := [2]int{x, y}
+				stateDepth, visited := stateDepths[key]
+				if !visited {
+					stateDepths[key] = depth
+					next = append(next, key)
+				} else if stateDepth < depth {
+					continue
+				}
+				ways[key] = min(ways[key], math.MaxInt-ways[current]) + ways[current]
+			}
+		}
+		for _, key := range next {
+			amounts := []int{key[0], key[1]}
+			if key[0] == key[1] {
+				amounts = amounts[:1]
+			}
+			for _, amount := range amounts {
+				if firstDepth, ok := depths[amount]; amount == 0 || ok && firstDepth < depth {
+					continue
+				}
+				depths[amount] = depth
+				solutions[amount] = min(solutions[amount], math.MaxInt-ways[key]) + ways[key]
+			}
+		}
+		layer = next
+	}
+	return solutions
+}
+
+// StepsTable returns the minimal number of steps needed to measure every
+// target from 1 to max(xMax, yMax) with jugs of capacities xMax and yMax,
+// computed from a single search.
+func StepsTable(xMax, yMax int) *models.StepsTable {
+	steps := MinSteps(xMax, yMax)
+	table := &models.StepsTable{
+		XCap:    xMax,
+		YCap:    yMax,
+		Targets: make([]*models.TargetSteps, 0, max(xMax, yMax)),
+	}
+	for target := 1; target <= max(xMax, yMax); target++ {
+		targetSteps, solvable := steps[target]
+		table.Targets = append(table.Targets, &models.TargetSteps{
+			Target:   target,
+			Solvable: solvable,
+			Steps:    targetSteps,
+		})
+	}
+	return table
+}
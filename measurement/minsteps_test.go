@@ -0,0 +1,77 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+func TestMinSteps(t *testing.T) {
+	for xMax := 1; xMax <= 12; xMax++ {
+		for yMax := 1; yMax <= 12; yMax++ {
+			steps := MinSteps(xMax, yMax)
+			for target := 1; target <= max(xMax, yMax); target++ {
+				solution, err := Measure(xMax, yMax, target)
+				if err != nil {
+					require.NotContainsf(t, steps, target, "x=%d y=%d z=%d", xMax, yMax, target)
+					continue
+				}
+				require.Equalf(t, len(solution.Steps), steps[target], "x=%d y=%d z=%d", xMax, yMax, target)
+			}
+		}
+	}
+}
+
+func TestStepsTable(t *testing.T) {
+	expectedOutput := &models.StepsTable{
+		XCap: 2,
+		YCap: 4,
+		Targets: []*models.TargetSteps{
+			{Target: 1},
+			{Target: 2, Solvable: true, Steps: 1},
+			{Target: 3},
+			{Target: 4, Solvable: true, Steps: 1},
+		},
+	}
+	require.Equal(t, expectedOutput, StepsTable(2, 4))
+}
+
+// countSolutions returns the number of sequences of the given number of
+// actions measuring the target with jugs of capacities xMax and yMax,
+// starting from the jugs holding x and y, by trying all of them.
+func countSolutions(xMax, yMax, x, y, target, steps int) int {
+	if steps == 0 {
+		return 0
+	}
+	var count int
+	for action := range actions {
+		nextX, nextY := move(x, y, xMax, yMax, action)
+		if nextX == target || nextY == target {
+			if steps == 1 {
+				count++
+			}
+			continue
+		}
+		count += countSolutions(xMax, yMax, nextX, nextY, target, steps-1)
+	}
+	return count
+}
+
+func TestOptimalSolutions(t *testing.T) {
+	for xMax := 1; xMax <= 5; xMax++ {
+		for yMax := 1; yMax <= 5; yMax++ {
+			steps := MinSteps(xMax, yMax)
+			solutions := OptimalSolutions(xMax, yMax)
+			require.Lenf(t, solutions, len(steps), "x=%d y=%d", xMax, yMax)
+			for target, targetSteps := range steps {
+				expected := countSolutions(xMax, yMax, 0, 0, target, targetSteps)
+				require.Equalf(t, expected, solutions[target], "x=%d y=%d z=%d", xMax, yMax, target)
+			}
+		}
+	}
+}
@@ -4,23 +4,289 @@
 
 package models
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // NewMeasurement represents the measurements for the water jug problem.
 type NewMeasurement struct {
-	XCap          int `json:"x_capacity" validate:"required,gt=0,lt=10000"`      // XCap represents the capacity of jug X.
-	YCap          int `json:"y_capacity" validate:"required,gt=0,lt=10000"`      // YCap represents the capacity of jug Y.
-	ZAmountWanted int `json:"z_amount_wanted" validate:"required,gt=0,lt=10000"` // ZAmountWanted represents the desired amount of water Z.
+	XCap          int      `json:"x_capacity" validate:"required,gt=0,lt=10000"`                             // XCap represents the capacity of jug X.
+	YCap          int      `json:"y_capacity" validate:"required,gt=0,lt=10000"`                             // YCap represents the capacity of jug Y.
+	ZAmountWanted int      `json:"z_amount_wanted" validate:"required_without=Goal,omitempty,gt=0,lt=10000"` // ZAmountWanted represents the desired amount of water Z.
+	Strategy      string   `json:"strategy,omitempty" validate:"omitempty,strategy"`                         // Strategy represents the solving strategy to use.
+	Goal          *Goal    `json:"goal,omitempty" validate:"excluded_with=Accumulate"`                       // Goal represents a custom goal, replacing "either jug holds Z".
+	Accumulate    bool     `json:"accumulate,omitempty"`                                                     // Accumulate tells whether Z is to be delivered into a receiving vessel.
+	MaxSteps      int      `json:"max_steps,omitempty" validate:"omitempty,gt=0,lt=100000"`                  // MaxSteps represents the maximum number of steps accepted.
+	ActionOrder   []string `json:"action_order,omitempty" validate:"omitempty,unique,dive,action"`           // ActionOrder represents the actions to try first, in order of preference.
+}
+
+// Goal represents a goal specification for the water jug problem.
+// Every condition that is set must hold for the goal to be reached.
+type Goal struct {
+	X          *int  `json:"x,omitempty" validate:"omitempty,gte=0,lt=10000"`              // X represents the exact amount wanted in jug X.
+	Y          *int  `json:"y,omitempty" validate:"omitempty,gte=0,lt=10000"`              // Y represents the exact amount wanted in jug Y.
+	AnyOf      []int `json:"any_of,omitempty" validate:"omitempty,dive,gte=0,lt=10000"`    // AnyOf represents amounts of which either jug must hold one.
+	Difference *int  `json:"difference,omitempty" validate:"omitempty,gt=-10000,lt=10000"` // Difference represents the wanted amount of X minus Y.
 }
 
 // Step represents a step in the solution to the water jug problem.
 type Step struct {
-	Number  int    `json:"step"`             // Number represents the step number.
-	BucketX int    `json:"bucketX"`          // BucketX represents the amount of water in jug X.
-	BucketY int    `json:"bucketY"`          // BucketY represents the amount of water in jug Y.
-	Action  string `json:"action"`           // Action represents the action taken in this step.
-	Status  string `json:"status,omitempty"` // Status represents the status of the step, if applicable.
+	Number   int    `json:"step"`               // Number represents the step number.
+	BucketX  int    `json:"bucketX"`            // BucketX represents the amount of water in jug X.
+	BucketY  int    `json:"bucketY"`            // BucketY represents the amount of water in jug Y.
+	Receiver *int   `json:"receiver,omitempty"` // Receiver represents the amount of water in the receiving vessel, in accumulation mode.
+	Action   string `json:"action"`             // Action represents the action taken in this step.
+	Status   string `json:"status,omitempty"`   // Status represents the status of the step, if applicable.
 }
 
 // Solution represents the solution to the water jug problem.
 type Solution struct {
-	Steps []*Step `json:"solution"` // Steps is a slice of steps representing the solution path.
+	Steps       []*Step      `json:"solution"`              // Steps is a slice of steps representing the solution path.
+	Strategy    string       `json:"strategy,omitempty"`    // Strategy represents the solving strategy that produced the solution.
+	StepLimit   *StepLimit   `json:"stepLimit,omitempty"`   // StepLimit tells whether the solution fits the maximum number of steps, if one was given.
+	Diagnostics *Diagnostics `json:"diagnostics,omitempty"` // Diagnostics represents statistics about the search, if requested.
+}
+
+// Diagnostics represents statistics about the search that produced a solution.
+type Diagnostics struct {
+	Strategy    string        `json:"strategy"`         // Strategy represents the solving strategy used.
+	Expanded    int           `json:"expanded"`         // Expanded represents the number of states whose successors were generated.
+	MaxFrontier int           `json:"maxFrontier"`      // MaxFrontier represents the largest number of states waiting to be expanded.
+	Visited     int           `json:"visited"`          // Visited represents the number of distinct states discovered.
+	SolverTime  string        `json:"solverTime"`       // SolverTime represents the time taken by the solver.
+	Layers      []*StateLayer `json:"layers,omitempty"` // Layers is a slice of the discovered states, if requested.
+}
+
+// StateLayer represents a state discovered by breadth-first search.
+type StateLayer struct {
+	BucketX  int  `json:"bucketX"`            // BucketX represents the amount of water in jug X.
+	BucketY  int  `json:"bucketY"`            // BucketY represents the amount of water in jug Y.
+	Receiver *int `json:"receiver,omitempty"` // Receiver represents the amount of water in the receiving vessel, in accumulation mode.
+	Layer    int  `json:"layer"`              // Layer represents the number of steps from the initial state.
+}
+
+// NoSolution represents the reason why a measurement has no solution.
+type NoSolution struct {
+	Reason    string        `json:"reason"`                    // Reason represents a stable code for the reason.
+	XCap      int           `json:"x_capacity,omitempty"`      // XCap represents the capacity of jug X, unless measuring with an inventory.
+	YCap      int           `json:"y_capacity,omitempty"`      // YCap represents the capacity of jug Y, unless measuring with an inventory.
+	Target    int           `json:"z_amount_wanted,omitempty"` // Target represents the desired amount of water Z, if any.
+	GCD       *int          `json:"gcd,omitempty"`             // GCD represents the greatest common divisor of the capacities, for gcd mismatches.
+	MaxSteps  int           `json:"maxSteps,omitempty"`        // MaxSteps represents the maximum number of steps, when exhausted.
+	MaxStates int           `json:"maxStates,omitempty"`       // MaxStates represents the number of states the search gave up after, when exhausted.
+	Optimum   *int          `json:"optimum,omitempty"`         // Optimum represents the length of the optimal solution, when known.
+	Pairs     []*NoSolution `json:"pairs,omitempty"`           // Pairs is a slice of the reasons why each pair of jugs of an inventory has no solution.
+}
+
+// StepLimit represents how the optimal solution relates to a maximum number of steps.
+type StepLimit struct {
+	MaxSteps int  `json:"maxSteps"`          // MaxSteps represents the maximum number of steps accepted.
+	Fits     bool `json:"fits"`              // Fits tells whether the optimal solution has at most MaxSteps steps.
+	Optimum  *int `json:"optimum,omitempty"` // Optimum represents the length of the optimal solution, when known.
+}
+
+// StateGraphQuery represents the parameters for exporting the state graph of a jug pair.
+type StateGraphQuery struct {
+	XCap   int    `json:"x_capacity" validate:"required,gt=0,lt=10000"`       // XCap represents the capacity of jug X.
+	YCap   int    `json:"y_capacity" validate:"required,gt=0,lt=10000"`       // YCap represents the capacity of jug Y.
+	Target int    `json:"target" validate:"omitempty,gt=0,lt=10000"`          // Target represents the amount whose optimal path is highlighted.
+	Format string `json:"format" validate:"omitempty,oneof=json dot graphml"` // Format represents the output format.
+}
+
+// StepsTableQuery represents the parameters for computing the steps table of a jug pair.
+type StepsTableQuery struct {
+	XCap   int    `json:"x_capacity" validate:"required,gt=0,lt=10000"` // XCap represents the capacity of jug X.
+	YCap   int    `json:"y_capacity" validate:"required,gt=0,lt=10000"` // YCap represents the capacity of jug Y.
+	Format string `json:"format" validate:"omitempty,oneof=json csv"`   // Format represents the output format.
+}
+
+// TargetSteps represents the minimal number of steps needed to measure a target.
+type TargetSteps struct {
+	Target   int  `json:"target"`          // Target represents the amount wanted.
+	Solvable bool `json:"solvable"`        // Solvable tells whether the target can be measured.
+	Steps    int  `json:"steps,omitempty"` // Steps represents the minimal number of steps, if solvable.
+}
+
+// StepsTable represents the minimal number of steps for every target of a jug pair.
+type StepsTable struct {
+	XCap    int            `json:"x_capacity"` // XCap represents the capacity of jug X.
+	YCap    int            `json:"y_capacity"` // YCap represents the capacity of jug Y.
+	Targets []*TargetSteps `json:"targets"`    // Targets is a slice of every target from 1 to the largest capacity.
+}
+
+// AnalyticsQuery represents the parameters for analyzing the states of a jug pair.
+type AnalyticsQuery struct {
+	XCap int `json:"x_capacity" validate:"required,gt=0,lt=10000"` // XCap represents the capacity of jug X.
+	YCap int `json:"y_capacity" validate:"required,gt=0,lt=10000"` // YCap represents the capacity of jug Y.
+}
+
+// StateRange represents the states with the same amount of water in jug X
+// and a range of amounts of water in jug Y.
+type StateRange struct {
+	BucketX     int `json:"bucketX"`     // BucketX represents the amount of water in jug X.
+	FromBucketY int `json:"fromBucketY"` // FromBucketY represents the smallest amount of water in jug Y.
+	ToBucketY   int `json:"toBucketY"`   // ToBucketY represents the largest amount of water in jug Y.
+}
+
+// StateAnalytics represents analytics over the graph of states of a jug pair.
+type StateAnalytics struct {
+	XCap                int           `json:"x_capacity"`          // XCap represents the capacity of jug X.
+	YCap                int           `json:"y_capacity"`          // YCap represents the capacity of jug Y.
+	TotalStates         int           `json:"totalStates"`         // TotalStates represents the number of states, reachable or not.
+	ReachableStates     int           `json:"reachableStates"`     // ReachableStates represents the number of states reachable from the initial one.
+	Diameter            int           `json:"diameter"`            // Diameter represents the largest number of steps between two reachable states.
+	InitialEccentricity int           `json:"initialEccentricity"` // InitialEccentricity represents the largest number of steps from the initial state to a reachable one.
+	HardestTargetSteps  int           `json:"hardestTargetSteps"`  // HardestTargetSteps represents the number of steps needed by the hardest targets.
+	HardestTargets      []int         `json:"hardestTargets"`      // HardestTargets is a slice of the targets needing the most steps.
+	UnreachableStates   []*StateRange `json:"unreachableStates"`   // UnreachableStates is a slice of the ranges of states that cannot be reached.
+}
+
+// GraphNode represents a reachable state of the jugs.
+type GraphNode struct {
+	ID      string `json:"id"`                // ID represents the node identifier.
+	BucketX int    `json:"bucketX"`           // BucketX represents the amount of water in jug X.
+	BucketY int    `json:"bucketY"`           // BucketY represents the amount of water in jug Y.
+	Initial bool   `json:"initial,omitempty"` // Initial tells whether this is the initial state.
+	OnPath  bool   `json:"onPath,omitempty"`  // OnPath tells whether the node is on the highlighted path.
+}
+
+// GraphEdge represents an action leading from one state to another.
+type GraphEdge struct {
+	From   string `json:"from"`             // From represents the source node identifier.
+	To     string `json:"to"`               // To represents the target node identifier.
+	Action string `json:"action"`           // Action represents the action taken.
+	OnPath bool   `json:"onPath,omitempty"` // OnPath tells whether the edge is on the highlighted path.
+}
+
+// Graph represents the graph of states reachable with a pair of jugs.
+type Graph struct {
+	XCap  int          `json:"x_capacity"` // XCap represents the capacity of jug X.
+	YCap  int          `json:"y_capacity"` // YCap represents the capacity of jug Y.
+	Nodes []*GraphNode `json:"nodes"`      // Nodes is a slice of the reachable states.
+	Edges []*GraphEdge `json:"edges"`      // Edges is a slice of the transitions between states.
+}
+
+// NewPuzzles represents the parameters for generating puzzles.
+type NewPuzzles struct {
+	Steps       int    `json:"steps" validate:"required,gte=2"`                // Steps represents the length of the optimal solution of every puzzle.
+	MinCapacity int    `json:"min_capacity" validate:"required,gt=0,lte=1000"` // MinCapacity represents the minimum jug capacity.
+	MaxCapacity int    `json:"max_capacity" validate:"required,gt=0,lte=1000"` // MaxCapacity represents the maximum jug capacity.
+	Count       int    `json:"count" validate:"omitempty,gt=0,lte=20"`         // Count represents the number of puzzles wanted, one by default.
+	Seed        *int64 `json:"seed,omitempty"`                                 // Seed represents the random seed, making the generation reproducible.
+}
+
+// Puzzle represents a water jug puzzle.
+type Puzzle struct {
+	XCap          int `json:"x_capacity"`      // XCap represents the capacity of jug X.
+	YCap          int `json:"y_capacity"`      // YCap represents the capacity of jug Y.
+	ZAmountWanted int `json:"z_amount_wanted"` // ZAmountWanted represents the desired amount of water Z.
+	Steps         int `json:"steps"`           // Steps represents the length of the optimal solution.
+}
+
+// GeneratedPuzzles represents the result of a puzzle generation.
+type GeneratedPuzzles struct {
+	Seed    int64     `json:"seed"`    // Seed represents the random seed used, to reproduce the generation.
+	Puzzles []*Puzzle `json:"puzzles"` // Puzzles is a slice of the generated puzzles.
+}
+
+// DailyPuzzle represents the puzzle of the day.
+type DailyPuzzle struct {
+	Date       string `json:"date"`       // Date represents the date of the puzzle, as YYYY-MM-DD.
+	Difficulty string `json:"difficulty"` // Difficulty represents how hard the puzzle is.
+	Puzzle
+}
+
+// DailyAttempt represents a procedure submitted to solve the puzzle of the day.
+type DailyAttempt struct {
+	Actions []string `json:"actions" validate:"required,min=1,dive,required"` // Actions is a slice of the actions taken, in order.
+}
+
+// DailyVerification represents the result of verifying a daily attempt.
+type DailyVerification struct {
+	Date     string    `json:"date"`               // Date represents the date of the puzzle, as YYYY-MM-DD.
+	Solved   bool      `json:"solved"`             // Solved tells whether the attempt solves the puzzle.
+	Steps    int       `json:"steps"`              // Steps represents the number of steps of the attempt.
+	Optimal  bool      `json:"optimal"`            // Optimal tells whether the attempt solves the puzzle in the fewest steps.
+	Solution *Solution `json:"solution,omitempty"` // Solution represents the optimal solution, revealed once the puzzle is solved.
+}
+
+// NewRecommendation represents the parameters for recommending a pair of jugs from an inventory.
+type NewRecommendation struct {
+	Inventory     []int  `json:"inventory" validate:"required,min=2,max=50,dive,gt=0,lt=10000"` // Inventory represents the capacities of the jugs available.
+	ZAmountWanted int    `json:"z_amount_wanted" validate:"required,gt=0,lt=10000"`             // ZAmountWanted represents the desired amount of water Z.
+	RankBy        string `json:"rank_by" validate:"omitempty,oneof=steps water score"`          // RankBy represents the ranking criterion, steps by default.
+}
+
+// RankedPair represents a pair of jugs able to measure the wanted amount.
+type RankedPair struct {
+	XCap      int     `json:"x_capacity"` // XCap represents the capacity of jug X.
+	YCap      int     `json:"y_capacity"` // YCap represents the capacity of jug Y.
+	Steps     int     `json:"steps"`      // Steps represents the length of the optimal solution.
+	WaterUsed int     `json:"waterUsed"`  // WaterUsed represents the amount of water drawn when filling jugs.
+	Score     float64 `json:"score"`      // Score combines steps and water used, relative to the best of each; lower is better.
+}
+
+// Recommendation represents the pairs of jugs ranked for measuring an amount.
+type Recommendation struct {
+	RankBy   string        `json:"rankBy"`   // RankBy represents the ranking criterion.
+	Pairs    []*RankedPair `json:"pairs"`    // Pairs is a slice of the pairs able to measure the amount, best first.
+	Solution *Solution     `json:"solution"` // Solution represents the optimal solution with the best pair.
+}
+
+// NewJob represents a measurement to be solved asynchronously.
+type NewJob struct {
+	NewMeasurement
+	CallbackURL    string `json:"callback_url,omitempty" validate:"omitempty,http_url"`                                    // CallbackURL represents where the finished job is to be posted.
+	CallbackSecret string `json:"callback_secret,omitempty" validate:"required_with=CallbackURL,omitempty,min=16,max=256"` // CallbackSecret represents the secret shared with the callback, to sign what's posted.
+}
+
+// Job represents a measurement solved asynchronously.
+type Job struct {
+	ID             string          `json:"id"`                    // ID represents the identifier of the job.
+	Status         string          `json:"status"`                // Status represents the state of the job: queued, running, succeeded, failed or canceled.
+	StatusURL      string          `json:"statusUrl"`             // StatusURL represents where the job can be polled.
+	Measurement    *NewMeasurement `json:"measurement"`           // Measurement represents the measurement to solve.
+	Progress       *JobProgress    `json:"progress,omitempty"`    // Progress represents how far the search has gone, once running.
+	Solution       *Solution       `json:"solution,omitempty"`    // Solution represents the solution, once succeeded.
+	Error          json.RawMessage `json:"error,omitempty"`       // Error represents the problem details of why the job failed.
+	CallbackURL    string          `json:"callbackUrl,omitempty"` // CallbackURL represents where the finished job is posted, if anywhere.
+	CallbackSecret string          `json:"-"`                     // CallbackSecret represents the secret signing what's posted, which is never disclosed nor stored.
+	Deliveries     []*Delivery     `json:"deliveries,omitempty"`  // Deliveries is a slice of the attempts to post the finished job.
+	CreatedAt      time.Time       `json:"createdAt"`             // CreatedAt represents when the job was submitted.
+	UpdatedAt      time.Time       `json:"updatedAt"`             // UpdatedAt represents when the job last changed.
+}
+
+// JobProgress represents how far the search of a job has gone.
+type JobProgress struct {
+	Expanded int `json:"expanded"` // Expanded represents the number of states whose successors were generated.
+	Visited  int `json:"visited"`  // Visited represents the number of distinct states discovered.
+}
+
+// Delivery represents an attempt to post a finished job to its callback URL.
+type Delivery struct {
+	Attempt    int       `json:"attempt"`              // Attempt represents the number of the attempt, starting at 1.
+	At         time.Time `json:"at"`                   // At represents when the attempt was made.
+	StatusCode int       `json:"statusCode,omitempty"` // StatusCode represents the status code the callback answered with, if it did.
+	Error      string    `json:"error,omitempty"`      // Error represents why the attempt failed, if it did.
+}
+
+// PlayMessage represents a message sent by a player in play mode.
+type PlayMessage struct {
+	Type   string `json:"type"`             // Type represents the kind of message: "action" or "hint".
+	Action string `json:"action,omitempty"` // Action represents the action to apply, for "action" messages.
+}
+
+// PlayEvent represents a message sent to a player in play mode. Every event
+// reports the amounts in the jugs and the number of moves so far.
+type PlayEvent struct {
+	Type      string `json:"type"`                // Type represents the kind of event: "state", "hint", "win" or "error".
+	BucketX   int    `json:"bucketX"`             // BucketX represents the amount of water in jug X.
+	BucketY   int    `json:"bucketY"`             // BucketY represents the amount of water in jug Y.
+	Moves     int    `json:"moves"`               // Moves represents the number of actions applied so far.
+	Action    string `json:"action,omitempty"`    // Action represents the action just applied, or the one suggested by a hint.
+	Remaining int    `json:"remaining,omitempty"` // Remaining represents the number of steps left to win, for hints.
+	Optimum   int    `json:"optimum,omitempty"`   // Optimum represents the number of steps of the shortest solution, for wins.
+	Reason    string `json:"reason,omitempty"`    // Reason represents the kind of error, for errors.
+	Error     string `json:"error,omitempty"`     // Error represents what went wrong, for errors.
 }
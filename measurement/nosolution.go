@@ -0,0 +1,158 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// Reasons why a problem has no solution.
+const (
+	// ReasonGCDMismatch means the target is not a multiple of the greatest
+	// common divisor of the capacities, so no sequence of actions measures it.
+	ReasonGCDMismatch = "gcd_mismatch"
+	// ReasonTargetExceedsCapacity means the target is larger than both jugs.
+	ReasonTargetExceedsCapacity = "target_exceeds_capacity"
+	// ReasonConstraintViolation means no reachable state satisfies the goal.
+	ReasonConstraintViolation = "constraint_violation"
+	// ReasonBudgetExhausted means every solution takes more than the maximum
+	// number of steps, or the search gave up after discovering too many states.
+	ReasonBudgetExhausted = "budget_exhausted"
+	// ReasonNoPair means no pair of jugs from an inventory measures the
+	// target, each for a reason of its own.
+	ReasonNoPair = "no_pair"
+)
+
+// ErrNoSolution is matched by every *NoSolutionError.
+var ErrNoSolution = errors.New("no solution")
+
+// NoSolutionError explains why a problem has no solution.
+type NoSolutionError struct {
+	Reason    string             // Reason is one of the Reason constants.
+	XCap      int                // XCap is the capacity of jug X.
+	YCap      int                // YCap is the capacity of jug Y.
+	Target    int                // Target is the amount wanted, if any.
+	GCD       int                // GCD is the greatest common divisor of the capacities, for ReasonGCDMismatch.
+	MaxSteps  int                // MaxSteps is the maximum number of steps, for ReasonBudgetExhausted.
+	MaxStates int                // MaxStates is the number of states the search gave up after, for ReasonBudgetExhausted.
+	Optimum   *int               // Optimum is the length of the optimal solution, for ReasonBudgetExhausted, when known.
+	Pairs     []*NoSolutionError // Pairs explains why each pair of jugs of an inventory has no solution, for ReasonNoPair.
+}
+
+// Error describes the reason why there's no solution.
+func (e *NoSolutionError) Error() string {
+	switch e.Reason {
+	case ReasonGCDMismatch:
+		return fmt.Sprintf("%v: %d is not a multiple of gcd(%d, %d) = %d", ErrNoSolution, e.Target, e.XCap, e.YCap, e.GCD)
+	case ReasonTargetExceedsCapacity:
+		return fmt.Sprintf("%v: %d exceeds both capacities, %d and %d", ErrNoSolution, e.Target, e.XCap, e.YCap)
+	case ReasonBudgetExhausted:
+		if e.MaxStates > 0 {
+			return fmt.Sprintf("%v within the search budget of %d states", ErrNoSolution, e.MaxStates)
+		}
+		if e.Optimum != nil {
+			return fmt.Sprintf("%v within %d steps: the optimal solution takes %d", ErrNoSolution, e.MaxSteps, *e.Optimum)
+		}
+		return fmt.Sprintf("%v within %d steps", ErrNoSolution, e.MaxSteps)
+	case ReasonNoPair:
+		return fmt.Sprintf("%v: no pair of jugs from the inventory can measure %d", ErrNoSolution, e.Target)
+	}
+	return fmt.Sprintf("%v: no reachable state satisfies the goal", ErrNoSolution)
+}
+
+// Is reports whether target is ErrNoSolution.
+func (e *NoSolutionError) Is(target error) bool {
+	return target == ErrNoSolution
+}
+
+// Model converts the error into its representation in responses.
+func (e *NoSolutionError) Model() *models.NoSolution {
+	noSolution := &models.NoSolution{
+		Reason:    e.Reason,
+		XCap:      e.XCap,
+		YCap:      e.YCap,
+		Target:    e.Target,
+		MaxSteps:  e.MaxSteps,
+		MaxStates: e.MaxStates,
+		Optimum:   e.Optimum,
+	}
+	if e.GCD > 0 {
+		gcd := e.GCD
+		noSolution.GCD = &gcd
+	}
+	for _, pair := range e.Pairs {
+		noSolution.Pairs = append(noSolution.Pairs, pair.Model())
+	}
+	return noSolution
+}
+
+// settle checks the solution found for the problem, or its absence, against
+// the problem's constraints. When there's no solution, the error explains
+// why; exhaustive tells whether the search that came empty-handed went
+// through every reachable state regardless of the maximum number of steps.
+// Otherwise, a solution within the maximum number of steps reports it.
+func (p Problem) settle(solution *models.Solution, exhaustive bool) (*models.Solution, error) {
+	if solution == nil {
+		return nil, p.explain(exhaustive)
+	}
+	if p.MaxSteps == 0 {
+		return solution, nil
+	}
+	optimum := len(solution.Steps)
+	if optimum > p.MaxSteps {
+		return nil, p.noSolution(ReasonBudgetExhausted, &optimum)
+	}
+	solution.StepLimit = &models.StepLimit{MaxSteps: p.MaxSteps, Fits: true, Optimum: &optimum}
+	return solution, nil
+}
+
+// explain returns the reason why the problem has no solution, knowing
+// that the search found none.
+func (p Problem) explain(exhaustive bool) error {
+	customGoal := p.Goal != nil && !p.Accumulate
+	switch {
+	case !customGoal && p.Target%gcd(p.XCap, p.YCap) != 0:
+		return p.noSolution(ReasonGCDMismatch, nil)
+	case !customGoal && !p.Accumulate && p.Target > max(p.XCap, p.YCap):
+		return p.noSolution(ReasonTargetExceedsCapacity, nil)
+	case p.MaxSteps == 0:
+		return p.noSolution(ReasonConstraintViolation, nil)
+	case p.Accumulate:
+		// any multiple of gcd(xMax, yMax) can be delivered, but looking for
+		// the optimum beyond the limit may take too long.
+		return p.noSolution(ReasonBudgetExhausted, nil)
+	}
+	if !exhaustive {
+		// searching again beyond the limit to find out the optimum, or that
+		// there's none, may take too long: it's only known when solutions
+		// come from a shortest-path tree.
+		return p.noSolution(ReasonBudgetExhausted, nil)
+	}
+	return p.noSolution(ReasonConstraintViolation, nil)
+}
+
+// noSolution returns the error explaining that the problem has no solution
+// for the given reason.
+func (p Problem) noSolution(reason string, optimum *int) *NoSolutionError {
+	err := &NoSolutionError{Reason: reason, XCap: p.XCap, YCap: p.YCap, Target: p.Target, Optimum: optimum}
+	switch reason {
+	case ReasonGCDMismatch:
+		err.GCD = gcd(p.XCap, p.YCap)
+	case ReasonBudgetExhausted:
+		err.MaxSteps = p.MaxSteps
+	}
+	return err
+}
+
+// statesExhausted returns the error explaining that the search for a solution
+// to the problem gave up after discovering maxStates states.
+func (p Problem) statesExhausted(maxStates int) *NoSolutionError {
+	err := p.noSolution(ReasonBudgetExhausted, nil)
+	err.MaxStates = maxStates
+	return err
+}
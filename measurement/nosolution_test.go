@@ -0,0 +1,116 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+func TestNoSolutionError(t *testing.T) {
+	two, six := 2, 6
+	testCases := []struct {
+		name           string
+		err            *NoSolutionError
+		expectedError  string
+		expectedOutput *models.NoSolution
+	}{
+		{
+			name:          "gcd mismatch",
+			err:           &NoSolutionError{Reason: ReasonGCDMismatch, XCap: 2, YCap: 4, Target: 3, GCD: 2},
+			expectedError: "no solution: 3 is not a multiple of gcd(2, 4) = 2",
+			expectedOutput: &models.NoSolution{
+				Reason: ReasonGCDMismatch,
+				XCap:   2,
+				YCap:   4,
+				Target: 3,
+				GCD:    &two,
+			},
+		},
+		{
+			name:          "target exceeds capacity",
+			err:           &NoSolutionError{Reason: ReasonTargetExceedsCapacity, XCap: 3, YCap: 5, Target: 8},
+			expectedError: "no solution: 8 exceeds both capacities, 3 and 5",
+			expectedOutput: &models.NoSolution{
+				Reason: ReasonTargetExceedsCapacity,
+				XCap:   3,
+				YCap:   5,
+				Target: 8,
+			},
+		},
+		{
+			name:          "constraint violation",
+			err:           &NoSolutionError{Reason: ReasonConstraintViolation, XCap: 5, YCap: 3},
+			expectedError: "no solution: no reachable state satisfies the goal",
+			expectedOutput: &models.NoSolution{
+				Reason: ReasonConstraintViolation,
+				XCap:   5,
+				YCap:   3,
+			},
+		},
+		{
+			name:          "budget exhausted",
+			err:           &NoSolutionError{Reason: ReasonBudgetExhausted, XCap: 3, YCap: 5, Target: 4, MaxSteps: 5, Optimum: &six},
+			expectedError: "no solution within 5 steps: the optimal solution takes 6",
+			expectedOutput: &models.NoSolution{
+				Reason:   ReasonBudgetExhausted,
+				XCap:     3,
+				YCap:     5,
+				Target:   4,
+				MaxSteps: 5,
+				Optimum:  &six,
+			},
+		},
+		{
+			name:          "budget exhausted, optimum unknown",
+			err:           &NoSolutionError{Reason: ReasonBudgetExhausted, XCap: 3, YCap: 5, Target: 13, MaxSteps: 5},
+			expectedError: "no solution within 5 steps",
+			expectedOutput: &models.NoSolution{
+				Reason:   ReasonBudgetExhausted,
+				XCap:     3,
+				YCap:     5,
+				Target:   13,
+				MaxSteps: 5,
+			},
+		},
+		{
+			name:          "search budget exhausted",
+			err:           &NoSolutionError{Reason: ReasonBudgetExhausted, XCap: 397, YCap: 401, Target: 9999, MaxStates: 100000},
+			expectedError: "no solution within the search budget of 100000 states",
+			expectedOutput: &models.NoSolution{
+				Reason:    ReasonBudgetExhausted,
+				XCap:      397,
+				YCap:      401,
+				Target:    9999,
+				MaxStates: 100000,
+			},
+		},
+		{
+			name: "no pair",
+			err: &NoSolutionError{Reason: ReasonNoPair, Target: 5, Pairs: []*NoSolutionError{
+				{Reason: ReasonTargetExceedsCapacity, XCap: 3, YCap: 4, Target: 5},
+				{Reason: ReasonGCDMismatch, XCap: 4, YCap: 6, Target: 5, GCD: 2},
+			}},
+			expectedError: "no solution: no pair of jugs from the inventory can measure 5",
+			expectedOutput: &models.NoSolution{
+				Reason: ReasonNoPair,
+				Target: 5,
+				Pairs: []*models.NoSolution{
+					{Reason: ReasonTargetExceedsCapacity, XCap: 3, YCap: 4, Target: 5},
+					{Reason: ReasonGCDMismatch, XCap: 4, YCap: 6, Target: 5, GCD: &two},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.EqualError(t, tc.err, tc.expectedError)
+			require.ErrorIs(t, tc.err, ErrNoSolution)
+			require.Equal(t, tc.expectedOutput, tc.err.Model())
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// lexFirstShortest enumerates every solution of the given length and
+// returns the actions of the first one in the problem's action order.
+func lexFirstShortest(p Problem, length int) []string {
+	var search func(s *state, path []string) []string
+	search = func(s *state, path []string) []string {
+		if len(path) == length {
+			if p.reached(s) {
+				return path
+			}
+			return nil
+		}
+		for _, nextState := range p.prioritize(transitions(s, p.XCap, p.YCap)) {
+			if found := search(nextState, append(path, nextState.action)); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+	return search(initialState(), nil)
+}
+
+func TestActionOrder(t *testing.T) {
+	orders := [][]string{
+		{"Transfer from bucket X to Y", "Transfer from bucket Y to X"},
+		{"Fill bucket Y", "Empty bucket Y", "Transfer from bucket Y to X"},
+		{"Empty bucket Y", "Transfer from bucket Y to X", "Fill bucket X", "Fill bucket Y", "Transfer from bucket X to Y", "Empty bucket X"},
+	}
+	for _, order := range orders {
+		for xMax := 1; xMax <= 5; xMax++ {
+			for yMax := 1; yMax <= 5; yMax++ {
+				for target := 1; target <= max(xMax, yMax); target++ {
+					expected, expectedErr := Measure(xMax, yMax, target)
+					p := Problem{XCap: xMax, YCap: yMax, Target: target, ActionOrder: order}
+					output, err := MeasureWith(BFS, p)
+					if expectedErr != nil {
+						require.Equal(t, expectedErr, err)
+						require.Nil(t, output)
+						continue
+					}
+					require.NoError(t, err)
+					var actions []string
+					for _, step := range output.Steps {
+						actions = append(actions, step.Action)
+					}
+					require.Equalf(t, lexFirstShortest(p, len(expected.Steps)), actions, "x=%d y=%d z=%d order=%v", xMax, yMax, target, order)
+					requireValidSolution(t, p, output)
+				}
+			}
+		}
+	}
+}
+
+func TestActionOrderUnsupportedStrategies(t *testing.T) {
+	p := Problem{XCap: 3, YCap: 5, Target: 4, ActionOrder: []string{"Fill bucket X"}}
+	for _, strategy := range []string{Bidirectional, Math, AStar} {
+		t.Run(strategy, func(t *testing.T) {
+			_, err := MeasureWith(strategy, p)
+			require.ErrorIs(t, err, ErrUnsupportedActionOrder)
+		})
+	}
+	_, err := BuildTree(3, 5).Solve(p)
+	require.ErrorIs(t, err, ErrUnsupportedActionOrder)
+}
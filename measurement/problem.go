@@ -0,0 +1,125 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"sort"
+
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// Problem describes an instance of the water jug problem.
+type Problem struct {
+	XCap   int  // XCap is the capacity of jug X.
+	YCap   int  // YCap is the capacity of jug Y.
+	Target int  // Target is the amount wanted in either jug, used when Goal is nil.
+	Goal   Goal // Goal is the compiled goal, if a custom one was specified.
+	// Accumulate tells whether Target is to be delivered into a receiving
+	// vessel, by pouring the jugs into it, instead of held in either jug.
+	Accumulate bool
+	// MaxSteps is the maximum number of steps accepted, or zero for no limit.
+	MaxSteps int
+	// ActionOrder lists actions in the order they are tried, before the
+	// ones left out, which keep their default order. Among solutions of the
+	// same length, the first in that lexicographic order is chosen.
+	ActionOrder []string
+}
+
+// NewProblem creates a problem from the given measurement, compiling its goal.
+func NewProblem(newMeasurement *models.NewMeasurement) (Problem, error) {
+	goal, err := CompileGoal(newMeasurement.Goal)
+	if err != nil {
+		return Problem{}, err
+	}
+	return Problem{
+		XCap:        newMeasurement.XCap,
+		YCap:        newMeasurement.YCap,
+		Target:      newMeasurement.ZAmountWanted,
+		Goal:        goal,
+		Accumulate:  newMeasurement.Accumulate,
+		MaxSteps:    newMeasurement.MaxSteps,
+		ActionOrder: newMeasurement.ActionOrder,
+	}, nil
+}
+
+// priority returns the rank of the given action in the problem's action order.
+// Actions left out of the order share the last rank.
+func (p Problem) priority(action string) int {
+	for i, a := range p.ActionOrder {
+		if a == action {
+			return i
+		}
+	}
+	return len(p.ActionOrder)
+}
+
+// prioritize sorts the states by the priority of the actions reaching them,
+// according to the problem's action order. Breadth-first search trying
+// actions in that order finds, among the shortest solutions, the first one
+// in lexicographic order of actions.
+func (p Problem) prioritize(states []*state) []*state {
+	if len(p.ActionOrder) > 0 {
+		sort.SliceStable(states, func(i, j int) bool {
+			return p.priority(states[i].action) < p.priority(states[j].action)
+		})
+	}
+	return states
+}
+
+// exceedsMaxSteps reports whether the given state lies beyond the problem's
+// maximum number of steps. Since breadth-first search generates states in
+// order of depth, it can stop at the first one.
+func (p Problem) exceedsMaxSteps(s *state) bool {
+	return p.MaxSteps > 0 && s.depth > p.MaxSteps
+}
+
+// reached reports whether the given state satisfies the problem's goal.
+func (p Problem) reached(s *state) bool {
+	if s.x == 0 && s.y == 0 {
+		// every search starts with empty jugs, so a goal can't be met
+		// by doing nothing or by getting back to them.
+		return false
+	}
+	if p.Goal != nil {
+		return p.Goal(s.x, s.y)
+	}
+	return isGoal(s, p.Target)
+}
+
+// feasible reports whether the problem may have a solution. Only the default
+// goal can be ruled out upfront; custom goals are decided by searching.
+func (p Problem) feasible() bool {
+	if p.Accumulate {
+		// every amount poured into the receiver is a multiple of gcd(xMax, yMax).
+		return p.Target%gcd(p.XCap, p.YCap) == 0
+	}
+	return p.Goal != nil || solvable(p.XCap, p.YCap, p.Target)
+}
+
+// goalStates returns every state other than the initial one that satisfies
+// the problem's goal and has at least one jug empty or full. Those are the
+// only goal states that can be reached, since every action leaves a jug
+// either empty or full.
+func (p Problem) goalStates() [][2]int {
+	seen := make(map[[2]int]bool)
+	var states [][2]int
+	add := func(x, y int) {
+		key := [2]int{x, y}
+		if seen[key] || !p.reached(&state{x: x, y: y}) {
+			return
+		}
+		seen[key] = true
+		states = append(states, key)
+	}
+	for x := 0; x <= p.XCap; x++ {
+		add(x, 0)
+		add(x, p.YCap)
+	}
+	for y := 0; y <= p.YCap; y++ {
+		add(0, y)
+		add(p.XCap, y)
+	}
+	return states
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// Ranking criteria for recommending pairs of jugs.
+const (
+	RankBySteps = "steps" // RankBySteps ranks pairs by the length of their optimal solution.
+	RankByWater = "water" // RankByWater ranks pairs by the water their optimal solution draws.
+	RankByScore = "score" // RankByScore ranks pairs by a score combining both.
+)
+
+// MaxInventoryCapacity is the largest product of the number of jugs in an
+// inventory and the capacity of its largest jug. Every pair of jugs is
+// searched, in time growing with the sum of their capacities.
+const MaxInventoryCapacity = 100_000
+
+// WaterUsed returns the amount of water drawn from the source by the
+// solution, that is, the water poured into jugs when filling them.
+func WaterUsed(solution *models.Solution) int {
+	var used, x, y int
+	for _, step := range solution.Steps {
+		switch step.Action {
+		case "Fill bucket X":
+			used += step.BucketX - x
+		case "Fill bucket Y":
+			used += step.BucketY - y
+		}
+		x, y = step.BucketX, step.BucketY
+	}
+	return used
+}
+
+// edgeStates is a set of the states reachable from empty jugs, where either
+// jug is always empty or full. Their number only grows with the sum of the
+// capacities, rather than with their product.
+type edgeStates struct {
+	xMax, yMax int
+	emptyX     []bool // emptyX tells, by the amount in jug Y, whether states where jug X is empty are in the set.
+	fullX      []bool // fullX tells, by the amount in jug Y, whether states where jug X is full are in the set.
+	emptyY     []bool // emptyY tells, by the amount in jug X, whether states where jug Y is empty are in the set.
+	fullY      []bool // fullY tells, by the amount in jug X, whether states where jug Y is full are in the set.
+}
+
+// newEdgeStates returns an empty set of states of jugs of capacities xMax and yMax.
+func newEdgeStates(xMax, yMax int) *edgeStates {
+	return &edgeStates{
+		xMax:   xMax,
+		yMax:   yMax,
+		emptyX: make([]bool, yMax+1),
+		fullX:  make([]bool, yMax+1),
+		emptyY: make([]bool, xMax+1),
+		fullY:  make([]bool, xMax+1),
+	}
+}
+
+// add adds the state where the jugs hold x and y to the set, reporting
+// whether it wasn't there yet.
+func (e *edgeStates) add(x, y int) bool {
+	var seen *bool
+	switch {
+	case x == 0:
+		seen = &e.emptyX[y]
+	case x == e.xMax:
+		seen = &e.fullX[y]
+	case y == 0:
+		seen = &e.emptyY[x]
+	default:
+		seen = &e.fullY[x]
+	}
+	if *seen {
+		return false
+	}
+	*seen = true
+	return true
+}
+
+// distance returns the number of steps of the solution Measure finds for
+// jugs of capacities xMax and yMax, along with the water it draws, by
+// searching the same way without keeping track of the path. It returns false
+// when the target can't be measured. Ranking every pair of a large inventory
+// with the breadth-first solver itself takes over ten times longer, as it
+// keeps every state discovered along with the path leading to it.
+func distance(xMax, yMax, target int) (steps, water int, ok bool) {
+	if !solvable(xMax, yMax, target) {
+		return 0, 0, false
+	}
+	type node struct {
+		x, y, depth, water int
+	}
+	visited := newEdgeStates(xMax, yMax)
+	visited.add(0, 0)
+	queue := []node{{}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for i := range actions {
+			x, y := move(current.x, current.y, xMax, yMax, i)
+			next := node{x: x, y: y, depth: current.depth + 1, water: current.water}
+			switch i {
+			case 0: // Fill bucket X
+				next.water += x - current.x
+			case 1: // Fill bucket Y
+				next.water += y - current.y
+			}
+			if x == target || y == target {
+				return next.depth, next.water, true
+			}
+			if visited.add(x, y) {
+				queue = append(queue, next)
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// Recommend evaluates every pair of jugs from the inventory and ranks the
+// ones able to measure the target according to rankBy, breaking ties by
+// the other criteria and then by the smallest capacities. Jug X is always
+// the smaller of the pair. Pairs are only measured by the length of the
+// optimal solution of the breadth-first solver and the water it draws, as
+// distance finds them; the solution itself is built by the solver for the
+// best pair alone. When no pair can measure the target, the returned
+// *NoSolutionError explains why for each of them.
+func Recommend(inventory []int, target int, rankBy string) (*models.Recommendation, error) {
+	seen := make(map[[2]int]bool)
+	var pairs []*models.RankedPair
+	var failures []*NoSolutionError
+	for i := range inventory {
+		for j := i + 1; j < len(inventory); j++ {
+			key := [2]int{min(inventory[i], inventory[j]), max(inventory[i], inventory[j])}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			steps, water, ok := distance(key[0], key[1], target)
+			if !ok {
+				var noSolution *NoSolutionError
+				// every state is searched when measuring a plain target.
+				errors.As(Problem{XCap: key[0], YCap: key[1], Target: target}.explain(true), &noSolution)
+				failures = append(failures, noSolution)
+				continue
+			}
+			pairs = append(pairs, &models.RankedPair{
+				XCap:      key[0],
+				YCap:      key[1],
+				Steps:     steps,
+				WaterUsed: water,
+			})
+		}
+	}
+	if len(pairs) == 0 {
+		return nil, &NoSolutionError{Reason: ReasonNoPair, Target: target, Pairs: failures}
+	}
+	bestSteps, bestWater := pairs[0].Steps, pairs[0].WaterUsed
+	for _, pair := range pairs {
+		bestSteps, bestWater = min(bestSteps, pair.Steps), min(bestWater, pair.WaterUsed)
+	}
+	for _, pair := range pairs {
+		score := float64(pair.Steps)/float64(bestSteps) + float64(pair.WaterUsed)/float64(max(bestWater, 1))
+		pair.Score = math.Round(score*100) / 100
+	}
+	// rankKey returns the criteria pairs are compared by, most significant first.
+	rankKey := func(pair *models.RankedPair) [4]float64 {
+		steps, water := float64(pair.Steps), float64(pair.WaterUsed)
+		switch rankBy {
+		case RankByWater:
+			return [4]float64{water, steps, float64(pair.XCap), float64(pair.YCap)}
+		case RankByScore:
+			return [4]float64{pair.Score, steps, float64(pair.XCap), float64(pair.YCap)}
+		default:
+			return [4]float64{steps, water, float64(pair.XCap), float64(pair.YCap)}
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		a, b := rankKey(pairs[i]), rankKey(pairs[j])
+		for k := range a {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return false
+	})
+	if rankBy == "" {
+		rankBy = RankBySteps
+	}
+	// the best pair is known to measure the target.
+	solution, _ := Measure(pairs[0].XCap, pairs[0].YCap, target)
+	return &models.Recommendation{
+		RankBy:   rankBy,
+		Pairs:    pairs,
+		Solution: solution,
+	}, nil
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+func TestWaterUsed(t *testing.T) {
+	solution, err := Measure(3, 5, 4)
+	require.NoError(t, err)
+	require.Equal(t, 10, WaterUsed(solution))
+	require.Equal(t, 0, WaterUsed(&models.Solution{}))
+}
+
+func TestDistance(t *testing.T) {
+	for xMax := 1; xMax <= 12; xMax++ {
+		for yMax := xMax; yMax <= 12; yMax++ {
+			for target := 1; target <= yMax; target++ {
+				steps, water, ok := distance(xMax, yMax, target)
+				solution, err := Measure(xMax, yMax, target)
+				if err != nil {
+					require.False(t, ok, "%d, %d, %d", xMax, yMax, target)
+					continue
+				}
+				require.True(t, ok, "%d, %d, %d", xMax, yMax, target)
+				require.Equal(t, len(solution.Steps), steps, "%d, %d, %d", xMax, yMax, target)
+				require.Equal(t, WaterUsed(solution), water, "%d, %d, %d", xMax, yMax, target)
+			}
+		}
+	}
+}
+
+func TestRecommend(t *testing.T) {
+	testCases := []struct {
+		name           string
+		inventory      []int
+		target         int
+		rankBy         string
+		expectedRankBy string
+		expectedPairs  []models.RankedPair
+		expectedError  error
+	}{
+		{
+			name:           "rank by steps by default",
+			inventory:      []int{7, 2, 4, 4},
+			target:         1,
+			expectedRankBy: RankBySteps,
+			expectedPairs: []models.RankedPair{
+				{XCap: 4, YCap: 7, Steps: 4, WaterUsed: 8, Score: 2.14},
+				{XCap: 2, YCap: 7, Steps: 6, WaterUsed: 7, Score: 2.5},
+			},
+		},
+		{
+			name:           "rank by water",
+			inventory:      []int{2, 4, 7},
+			target:         1,
+			rankBy:         RankByWater,
+			expectedRankBy: RankByWater,
+			expectedPairs: []models.RankedPair{
+				{XCap: 2, YCap: 7, Steps: 6, WaterUsed: 7, Score: 2.5},
+				{XCap: 4, YCap: 7, Steps: 4, WaterUsed: 8, Score: 2.14},
+			},
+		},
+		{
+			name:           "rank by score",
+			inventory:      []int{2, 4, 7},
+			target:         1,
+			rankBy:         RankByScore,
+			expectedRankBy: RankByScore,
+			expectedPairs: []models.RankedPair{
+				{XCap: 4, YCap: 7, Steps: 4, WaterUsed: 8, Score: 2.14},
+				{XCap: 2, YCap: 7, Steps: 6, WaterUsed: 7, Score: 2.5},
+			},
+		},
+		{
+			name:      "no pair measures the target",
+			inventory: []int{3, 4, 6, 4},
+			target:    5,
+			expectedError: &NoSolutionError{Reason: ReasonNoPair, Target: 5, Pairs: []*NoSolutionError{
+				{Reason: ReasonTargetExceedsCapacity, XCap: 3, YCap: 4, Target: 5},
+				{Reason: ReasonGCDMismatch, XCap: 3, YCap: 6, Target: 5, GCD: 3},
+				{Reason: ReasonGCDMismatch, XCap: 4, YCap: 6, Target: 5, GCD: 2},
+				{Reason: ReasonGCDMismatch, XCap: 4, YCap: 4, Target: 5, GCD: 4},
+			}},
+		},
+		{
+			name:      "target exceeds every jug",
+			inventory: []int{7, 3, 5},
+			target:    8,
+			expectedError: &NoSolutionError{Reason: ReasonNoPair, Target: 8, Pairs: []*NoSolutionError{
+				{Reason: ReasonTargetExceedsCapacity, XCap: 3, YCap: 7, Target: 8},
+				{Reason: ReasonTargetExceedsCapacity, XCap: 5, YCap: 7, Target: 8},
+				{Reason: ReasonTargetExceedsCapacity, XCap: 3, YCap: 5, Target: 8},
+			}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := Recommend(tc.inventory, tc.target, tc.rankBy)
+			if tc.expectedError != nil {
+				require.Nil(t, output)
+				require.Equal(t, tc.expectedError, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedRankBy, output.RankBy)
+			require.Len(t, output.Pairs, len(tc.expectedPairs))
+			for i, pair := range output.Pairs {
+				require.Equal(t, tc.expectedPairs[i], *pair)
+			}
+			best := output.Pairs[0]
+			solution, err := Measure(best.XCap, best.YCap, tc.target)
+			require.NoError(t, err)
+			require.Equal(t, solution, output.Solution)
+		})
+	}
+}
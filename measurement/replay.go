@@ -0,0 +1,73 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnknownAction is returned when an action is not one of the six actions.
+	ErrUnknownAction = errors.New("unknown action")
+	// ErrIllegalAction is returned when an action does not change the jugs,
+	// like filling a full jug or pouring from an empty one.
+	ErrIllegalAction = errors.New("illegal action")
+)
+
+// Apply applies the given action to jugs holding x and y, returning
+// the resulting amounts. The same rules used by the solvers apply.
+func Apply(xMax, yMax, x, y int, action string) (int, int, error) {
+	for i, name := range actions {
+		if name != action {
+			continue
+		}
+		nextX, nextY := move(x, y, xMax, yMax, i)
+		if nextX == x && nextY == y {
+			return x, y, fmt.Errorf(`%w: "%s" with X=%d and Y=%d`, ErrIllegalAction, action, x, y)
+		}
+		return nextX, nextY, nil
+	}
+	return x, y, fmt.Errorf(`%w: "%s"`, ErrUnknownAction, action)
+}
+
+// Replay applies the given actions, starting with both jugs empty, and
+// reports whether the problem's goal holds after the last one.
+func Replay(p Problem, actions []string) (bool, error) {
+	var x, y int
+	for i, action := range actions {
+		var err error
+		if x, y, err = Apply(p.XCap, p.YCap, x, y, action); err != nil {
+			return false, fmt.Errorf("step %d: %w", i+1, err)
+		}
+	}
+	return len(actions) > 0 && p.reached(&state{x: x, y: y}), nil
+}
+
+// Reached reports whether jugs holding x and y satisfy the problem's goal.
+func (p Problem) Reached(x, y int) bool {
+	return p.reached(&state{x: x, y: y})
+}
+
+// Hint returns the first action of the shortest way to the problem's goal
+// from jugs holding x and y, along with the number of steps it takes. No
+// action is returned when the goal is already reached. The problem's maximum
+// number of steps is ignored, and accumulation mode is not supported. When
+// there's no way to the goal, the returned *NoSolutionError explains why.
+func Hint(p Problem, x, y int) (string, int, error) {
+	if p.Reached(x, y) {
+		return "", 0, nil
+	}
+	p.MaxSteps = 0
+	final := bfsFrom(p, &state{x: x, y: y, action: "Start"}, nil)
+	if final == nil {
+		return "", 0, p.explain(true)
+	}
+	first := final
+	for first.prev.prev != nil {
+		first = first.prev
+	}
+	return first.action, final.depth, nil
+}
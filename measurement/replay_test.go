@@ -0,0 +1,134 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplay(t *testing.T) {
+	testCases := []struct {
+		name           string
+		actions        []string
+		expectedSolved bool
+		expectedError  error
+	}{
+		{
+			name: "optimal solution",
+			actions: []string{
+				"Fill bucket Y",
+				"Transfer from bucket Y to X",
+				"Empty bucket X",
+				"Transfer from bucket Y to X",
+				"Fill bucket Y",
+				"Transfer from bucket Y to X",
+			},
+			expectedSolved: true,
+		},
+		{
+			name:    "goal not reached",
+			actions: []string{"Fill bucket X", "Transfer from bucket X to Y"},
+		},
+		{
+			name:          "unknown action",
+			actions:       []string{"Fill bucket X", "Drink bucket X"},
+			expectedError: errors.New(`step 2: unknown action: "Drink bucket X"`),
+		},
+		{
+			name:          "illegal action",
+			actions:       []string{"Empty bucket Y"},
+			expectedError: errors.New(`step 1: illegal action: "Empty bucket Y" with X=0 and Y=0`),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			solved, err := Replay(Problem{XCap: 3, YCap: 5, Target: 4}, tc.actions)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.expectedError)
+				}
+				require.Equal(t, tc.expectedSolved, solved)
+			}
+		})
+	}
+}
+
+func TestHint(t *testing.T) {
+	testCases := []struct {
+		name              string
+		problem           Problem
+		x                 int
+		y                 int
+		expectedAction    string
+		expectedRemaining int
+		expectedError     error
+	}{
+		{
+			name:              "empty jugs",
+			problem:           Problem{XCap: 3, YCap: 5, Target: 4},
+			expectedAction:    "Fill bucket Y",
+			expectedRemaining: 6,
+		},
+		{
+			name:              "halfway",
+			problem:           Problem{XCap: 3, YCap: 5, Target: 4},
+			x:                 3,
+			y:                 2,
+			expectedAction:    "Empty bucket X",
+			expectedRemaining: 4,
+		},
+		{
+			name:              "off the optimal path",
+			problem:           Problem{XCap: 3, YCap: 5, Target: 4},
+			x:                 3,
+			expectedAction:    "Fill bucket Y",
+			expectedRemaining: 7,
+		},
+		{
+			name:              "ignores the maximum number of steps",
+			problem:           Problem{XCap: 3, YCap: 5, Target: 4, MaxSteps: 2},
+			x:                 3,
+			y:                 2,
+			expectedAction:    "Empty bucket X",
+			expectedRemaining: 4,
+		},
+		{
+			name:    "goal reached",
+			problem: Problem{XCap: 3, YCap: 5, Target: 4},
+			x:       3,
+			y:       4,
+		},
+		{
+			name:          "no solution",
+			problem:       Problem{XCap: 2, YCap: 6, Target: 5},
+			expectedError: errors.New("no solution: 5 is not a multiple of gcd(2, 6) = 2"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			action, remaining, err := Hint(tc.problem, tc.x, tc.y)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.expectedError)
+				}
+				require.Equal(t, tc.expectedAction, action)
+				require.Equal(t, tc.expectedRemaining, remaining)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// Names of the built-in solving strategies.
+const (
+	BFS           = "bfs"
+	Bidirectional = "bidirectional"
+	Math          = "math"
+	AStar         = "astar"
+)
+
+// DefaultStrategy is the strategy used when none is specified.
+const DefaultStrategy = BFS
+
+// SolverVersion identifies the behaviour of the built-in solvers. It must be
+// bumped whenever a change makes them return different solutions for the same
+// problem, so that solutions cached by clients are not mistaken for current ones.
+const SolverVersion = "1"
+
+var (
+	// ErrUnsupportedGoal is returned by strategies that cannot solve custom goals.
+	ErrUnsupportedGoal = errors.New("strategy does not support custom goals")
+	// ErrUnsupportedActionOrder is returned by strategies that cannot honour an action priority order.
+	ErrUnsupportedActionOrder = errors.New("strategy does not support action priority orders")
+)
+
+// Solver defines the interface for a water jug solving strategy.
+type Solver interface {
+	// Solve returns the shortest solution for the given problem,
+	// or nil if there is none.
+	Solve(p Problem) (*models.Solution, error)
+}
+
+// SolverFunc is an adapter to allow the use of ordinary functions as solvers.
+type SolverFunc func(p Problem) (*models.Solution, error)
+
+// Solve calls f(p).
+func (f SolverFunc) Solve(p Problem) (*models.Solution, error) {
+	return f(p)
+}
+
+// instrumentedSolver is implemented by the built-in solvers, which are able
+// to collect statistics about their search.
+type instrumentedSolver interface {
+	solveWithStats(p Problem, stats *searchStats) (*models.Solution, error)
+}
+
+// searchFunc is an adapter to allow the use of search functions collecting
+// statistics as solvers.
+type searchFunc func(p Problem, stats *searchStats) (*models.Solution, error)
+
+// Solve calls f(p, nil).
+func (f searchFunc) Solve(p Problem) (*models.Solution, error) {
+	return f(p, nil)
+}
+
+// solveWithStats calls f(p, stats).
+func (f searchFunc) solveWithStats(p Problem, stats *searchStats) (*models.Solution, error) {
+	return f(p, stats)
+}
+
+// For ease of unit testing.
+var (
+	now   = time.Now
+	since = time.Since
+)
+
+// registry holds the available solvers indexed by strategy name.
+var registry = struct {
+	sync.RWMutex
+	solvers map[string]Solver
+}{
+	solvers: map[string]Solver{
+		BFS:           searchFunc(measureBFS),
+		Bidirectional: searchFunc(measureBidirectional),
+		Math:          searchFunc(measureMath),
+		AStar:         &aStar{heuristic: bezoutHeuristic},
+	},
+}
+
+// Register makes a solver available under the given strategy name.
+// Registering an existing name replaces the previous solver.
+func Register(name string, solver Solver) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.solvers[name] = solver
+}
+
+// Lookup returns the solver registered under the given strategy name.
+func Lookup(name string) (Solver, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+	solver, ok := registry.solvers[name]
+	if !ok {
+		return nil, fmt.Errorf(`unknown strategy "%s"`, name)
+	}
+	return solver, nil
+}
+
+// Strategies returns the names of all registered strategies, sorted.
+func Strategies() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	names := make([]string, 0, len(registry.solvers))
+	for name := range registry.solvers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MeasureWith calculates the solution to the given problem using the
+// named strategy. The returned solution reports the strategy that produced it
+// and, when the problem has a maximum number of steps, that it fits. When
+// there's no solution, the returned *NoSolutionError explains why.
+func MeasureWith(strategy string, p Problem) (*models.Solution, error) {
+	return measureWith(strategy, p, nil)
+}
+
+// Diagnose calculates the solution to the given problem like MeasureWith,
+// attaching statistics about the search to it. When layers is true, the
+// breadth-first search strategy also reports the layer in which every state
+// was discovered. Solvers registered from outside this package only report
+// their strategy and time.
+func Diagnose(strategy string, p Problem, layers bool) (*models.Solution, error) {
+	stats := &searchStats{recordLayers: layers && strategy == BFS}
+	start := now()
+	solution, err := measureWith(strategy, p, stats)
+	elapsed := since(start)
+	if solution != nil {
+		solution.Diagnostics = stats.diagnostics(strategy, elapsed, p.Accumulate)
+	}
+	return solution, err
+}
+
+// MeasureWithProgress calculates the solution to the given problem like
+// MeasureWith, calling progress every ProgressInterval expanded states with
+// the number of states expanded and discovered so far. Solvers registered
+// from outside this package don't report progress.
+func MeasureWithProgress(strategy string, p Problem, progress func(expanded, visited int)) (*models.Solution, error) {
+	return measureWith(strategy, p, &searchStats{progress: progress})
+}
+
+// measureWith calculates the solution to the given problem using the
+// named strategy, collecting statistics about the search when the solver
+// is able to.
+func measureWith(strategy string, p Problem, stats *searchStats) (*models.Solution, error) {
+	solver, err := Lookup(strategy)
+	if err != nil {
+		return nil, err
+	}
+	var solution *models.Solution
+	if s, ok := solver.(instrumentedSolver); ok && stats != nil {
+		solution, err = s.solveWithStats(p, stats)
+	} else {
+		solution, err = solver.Solve(p)
+	}
+	if err != nil {
+		return nil, err
+	}
+	solution, err = p.settle(solution, false)
+	if err != nil {
+		return nil, err
+	}
+	solution.Strategy = strategy
+	return solution, nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// requireValidSolution asserts that every step of the solution is a legal
+// action from the previous one and that the last step reaches the goal.
+func requireValidSolution(t *testing.T, p Problem, solution *models.Solution) {
+	t.Helper()
+	current := initialState()
+	for i, step := range solution.Steps {
+		require.Equal(t, i+1, step.Number)
+		var legal bool
+		for _, nextState := range transitions(current, p.XCap, p.YCap) {
+			if nextState.x == step.BucketX && nextState.y == step.BucketY && nextState.action == step.Action {
+				legal = true
+				break
+			}
+		}
+		require.Truef(t, legal, "step %d is not a legal action", step.Number)
+		current = &state{x: step.BucketX, y: step.BucketY}
+	}
+	last := solution.Steps[len(solution.Steps)-1]
+	require.True(t, p.reached(&state{x: last.BucketX, y: last.BucketY}))
+	require.Equal(t, "Solved", last.Status)
+}
+
+func TestStrategiesMatchBFS(t *testing.T) {
+	for _, strategy := range Strategies() {
+		t.Run(strategy, func(t *testing.T) {
+			solver, err := Lookup(strategy)
+			require.NoError(t, err)
+			for xMax := 1; xMax <= 12; xMax++ {
+				for yMax := 1; yMax <= 12; yMax++ {
+					for target := 1; target <= max(xMax, yMax); target++ {
+						p := Problem{XCap: xMax, YCap: yMax, Target: target}
+						expected, expectedErr := Measure(xMax, yMax, target)
+						output, err := solver.Solve(p)
+						require.NoError(t, err)
+						if expectedErr != nil {
+							require.Nilf(t, output, "x=%d y=%d z=%d", xMax, yMax, target)
+							continue
+						}
+						require.NotNilf(t, output, "x=%d y=%d z=%d", xMax, yMax, target)
+						require.Lenf(t, output.Steps, len(expected.Steps), "x=%d y=%d z=%d", xMax, yMax, target)
+						requireValidSolution(t, p, output)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	testCases := []struct {
+		name          string
+		strategy      string
+		expectedError error
+	}{
+		{
+			name:     "known strategy",
+			strategy: AStar,
+		},
+		{
+			name:          "unknown strategy",
+			strategy:      "dfs",
+			expectedError: errors.New(`unknown strategy "dfs"`),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			solver, err := Lookup(tc.strategy)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error to occur, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error to occur, got nil`)
+				}
+				require.NotNil(t, solver)
+			}
+		})
+	}
+}
+
+func TestMeasureWith(t *testing.T) {
+	Register("test", SolverFunc(func(p Problem) (*models.Solution, error) {
+		return &models.Solution{}, nil
+	}))
+	defer func() {
+		registry.Lock()
+		delete(registry.solvers, "test")
+		registry.Unlock()
+	}()
+	require.Contains(t, Strategies(), "test")
+	p := Problem{XCap: 2, YCap: 100, Target: 96}
+	output, err := MeasureWith("test", p)
+	require.NoError(t, err)
+	require.Equal(t, &models.Solution{Strategy: "test"}, output)
+	_, err = MeasureWith("dfs", p)
+	require.Error(t, err)
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"time"
+
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// searchStats holds counters collected while searching the state space.
+// A nil *searchStats is valid and collects nothing.
+type searchStats struct {
+	expanded     int      // expanded is the number of states whose successors were generated.
+	maxFrontier  int      // maxFrontier is the largest number of states waiting to be expanded.
+	visited      int      // visited is the number of distinct states discovered.
+	recordLayers bool     // recordLayers tells whether discovered states are kept in layers.
+	layers       []*state // layers holds the discovered states, in discovery order.
+	// progress, when set, is called every ProgressInterval expanded states.
+	progress func(expanded, visited int)
+}
+
+// ProgressInterval is the number of states expanded between two progress reports.
+const ProgressInterval = 1024
+
+// expand records that a state had its successors generated.
+func (s *searchStats) expand() {
+	if s == nil {
+		return
+	}
+	s.expanded++
+	if s.progress != nil && s.expanded%ProgressInterval == 0 {
+		s.progress(s.expanded, s.visited)
+	}
+}
+
+// frontier records the current number of states waiting to be expanded.
+func (s *searchStats) frontier(n int) {
+	if s != nil {
+		s.maxFrontier = max(s.maxFrontier, n)
+	}
+}
+
+// visit records that a new state was discovered.
+func (s *searchStats) visit() {
+	if s != nil {
+		s.visited++
+	}
+}
+
+// discover records that breadth-first search discovered the given state,
+// keeping it when layers are recorded.
+func (s *searchStats) discover(st *state) {
+	if s == nil {
+		return
+	}
+	s.visited++
+	if s.recordLayers {
+		s.layers = append(s.layers, st)
+	}
+}
+
+// diagnostics converts the counters into the diagnostics of a search
+// performed with the given strategy, which took the given time.
+func (s *searchStats) diagnostics(strategy string, elapsed time.Duration, accumulate bool) *models.Diagnostics {
+	diagnostics := &models.Diagnostics{
+		Strategy:    strategy,
+		Expanded:    s.expanded,
+		MaxFrontier: s.maxFrontier,
+		Visited:     s.visited,
+		SolverTime:  elapsed.String(),
+	}
+	for _, st := range s.layers {
+		layer := &models.StateLayer{BucketX: st.x, BucketY: st.y, Layer: st.depth}
+		if accumulate {
+			receiver := st.receiver
+			layer.Receiver = &receiver
+		}
+		diagnostics.Layers = append(diagnostics.Layers, layer)
+	}
+	return diagnostics
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+func TestDiagnose(t *testing.T) {
+	testCases := []struct {
+		name           string
+		strategy       string
+		problem        Problem
+		layers         bool
+		expectedLayers bool
+	}{
+		{
+			name:     "bfs",
+			strategy: BFS,
+			problem:  Problem{XCap: 3, YCap: 5, Target: 4},
+		},
+		{
+			name:           "bfs with layers",
+			strategy:       BFS,
+			problem:        Problem{XCap: 3, YCap: 5, Target: 4},
+			layers:         true,
+			expectedLayers: true,
+		},
+		{
+			name:           "accumulation with layers",
+			strategy:       BFS,
+			problem:        Problem{XCap: 3, YCap: 5, Target: 13, Accumulate: true},
+			layers:         true,
+			expectedLayers: true,
+		},
+		{
+			name:     "bidirectional",
+			strategy: Bidirectional,
+			problem:  Problem{XCap: 3, YCap: 5, Target: 4},
+			layers:   true,
+		},
+		{
+			name:     "math",
+			strategy: Math,
+			problem:  Problem{XCap: 3, YCap: 5, Target: 4},
+		},
+		{
+			name:     "astar",
+			strategy: AStar,
+			problem:  Problem{XCap: 3, YCap: 5, Target: 4},
+			layers:   true,
+		},
+	}
+	originalNow, originalSince := now, since
+	defer func() {
+		now, since = originalNow, originalSince
+	}()
+	now = func() time.Time { return time.Time{} }
+	since = func(time.Time) time.Duration { return time.Millisecond }
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expected, err := MeasureWith(tc.strategy, tc.problem)
+			require.NoError(t, err)
+			output, err := Diagnose(tc.strategy, tc.problem, tc.layers)
+			require.NoError(t, err)
+			diagnostics := output.Diagnostics
+			require.NotNil(t, diagnostics)
+			output.Diagnostics = nil
+			require.Equal(t, expected, output)
+			require.Equal(t, tc.strategy, diagnostics.Strategy)
+			require.Equal(t, "1ms", diagnostics.SolverTime)
+			require.Positive(t, diagnostics.Expanded)
+			require.Positive(t, diagnostics.MaxFrontier)
+			require.GreaterOrEqual(t, diagnostics.Visited, len(expected.Steps))
+			if !tc.expectedLayers {
+				require.Nil(t, diagnostics.Layers)
+				return
+			}
+			require.Len(t, diagnostics.Layers, diagnostics.Visited)
+			require.Equal(t, 0, diagnostics.Layers[0].Layer)
+			for i, layer := range diagnostics.Layers {
+				require.Equal(t, tc.problem.Accumulate, layer.Receiver != nil)
+				if i > 0 {
+					require.GreaterOrEqual(t, layer.Layer, diagnostics.Layers[i-1].Layer)
+				}
+			}
+			last := diagnostics.Layers[len(diagnostics.Layers)-1]
+			require.Equal(t, len(expected.Steps), last.Layer)
+		})
+	}
+}
+
+func TestDiagnoseLayers(t *testing.T) {
+	unreachable := Problem{XCap: 4, YCap: 9, Goal: func(x, y int) bool { return false }}
+	output, err := Diagnose(BFS, unreachable, true)
+	require.ErrorIs(t, err, ErrNoSolution)
+	require.Nil(t, output)
+	// an unreachable goal makes the search discover every reachable state.
+	stats := searchStats{recordLayers: true}
+	bfs(unreachable, &stats)
+	require.Len(t, stats.layers, len(BuildTree(4, 9).nodes))
+	for _, s := range stats.layers[1:] {
+		x, y := s.x, s.y
+		p := Problem{XCap: 4, YCap: 9, Goal: func(a, b int) bool { return a == x && b == y }}
+		require.Lenf(t, solutionFrom(bfs(p, nil)).Steps, s.depth, "x=%d y=%d", x, y)
+	}
+}
+
+func TestDiagnoseRegisteredSolver(t *testing.T) {
+	Register("stub", SolverFunc(func(p Problem) (*models.Solution, error) {
+		return &models.Solution{Steps: []*models.Step{{Number: 1}}}, nil
+	}))
+	defer func() {
+		registry.Lock()
+		delete(registry.solvers, "stub")
+		registry.Unlock()
+	}()
+	output, err := Diagnose("stub", Problem{XCap: 3, YCap: 5, Target: 4}, true)
+	require.NoError(t, err)
+	require.Equal(t, "stub", output.Diagnostics.Strategy)
+	require.Zero(t, output.Diagnostics.Expanded)
+	require.Nil(t, output.Diagnostics.Layers)
+}
+
+func TestMeasureWithProgress(t *testing.T) {
+	problem := Problem{XCap: 997, YCap: 1000, Target: 500}
+	expected, err := MeasureWith(BFS, problem)
+	require.NoError(t, err)
+	var reports [][2]int
+	output, err := MeasureWithProgress(BFS, problem, func(expanded, visited int) {
+		reports = append(reports, [2]int{expanded, visited})
+	})
+	require.NoError(t, err)
+	require.Equal(t, expected, output)
+	require.NotEmpty(t, reports)
+	for i, report := range reports {
+		require.Equal(t, (i+1)*ProgressInterval, report[0])
+		require.GreaterOrEqual(t, report[1], report[0])
+	}
+}
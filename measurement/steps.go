@@ -0,0 +1,66 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import "github.com/tiagomelo/golang-waterjug-api/measurement/models"
+
+// StepIterator yields the steps of a breadth-first search solution one at
+// a time, building each of them only when it's asked for.
+type StepIterator struct {
+	states     []*state // states holds the path to the goal backwards, the initial state last.
+	accumulate bool     // accumulate tells whether steps report the receiver's level.
+	number     int      // number is the number of the last step yielded.
+}
+
+// IterateSteps searches for the solution to the given problem breadth-first
+// and returns an iterator over its steps. When there's none, the returned
+// *NoSolutionError explains why.
+func IterateSteps(p Problem) (*StepIterator, error) {
+	var final *state
+	if p.Accumulate {
+		var err error
+		if final, err = accumulationBFS(p, nil); err != nil {
+			return nil, err
+		}
+	} else {
+		final = bfs(p, nil)
+	}
+	if final == nil {
+		return nil, p.explain(false)
+	}
+	it := &StepIterator{accumulate: p.Accumulate}
+	for s := final; s != nil; s = s.prev {
+		it.states = append(it.states, s)
+	}
+	return it, nil
+}
+
+// Len returns the number of steps of the solution.
+func (it *StepIterator) Len() int {
+	return len(it.states) - 1
+}
+
+// Next returns the next step of the solution, and false once every step
+// has been yielded.
+func (it *StepIterator) Next() (*models.Step, bool) {
+	i := len(it.states) - 2 - it.number // skip the initial state.
+	if i < 0 {
+		return nil, false
+	}
+	it.number++
+	s := it.states[i]
+	step := &models.Step{
+		Number:  it.number,
+		BucketX: s.x,
+		BucketY: s.y,
+		Action:  s.action,
+		Status:  s.status,
+	}
+	if it.accumulate {
+		receiver := s.receiver
+		step.Receiver = &receiver
+	}
+	return step, true
+}
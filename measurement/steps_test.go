@@ -0,0 +1,63 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+func TestIterateSteps(t *testing.T) {
+	testCases := []struct {
+		name          string
+		problem       Problem
+		expectedError string
+	}{
+		{
+			name:    "happy path",
+			problem: Problem{XCap: 3, YCap: 5, Target: 4},
+		},
+		{
+			name:    "one step",
+			problem: Problem{XCap: 1, YCap: 2, Target: 2},
+		},
+		{
+			name:    "accumulation",
+			problem: Problem{XCap: 3, YCap: 5, Target: 13, Accumulate: true},
+		},
+		{
+			name:          "no solution",
+			problem:       Problem{XCap: 2, YCap: 6, Target: 5},
+			expectedError: "no solution: 5 is not a multiple of gcd(2, 6) = 2",
+		},
+		{
+			name:          "too many steps",
+			problem:       Problem{XCap: 3, YCap: 5, Target: 4, MaxSteps: 2},
+			expectedError: "no solution within 2 steps",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			it, err := IterateSteps(tc.problem)
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			expected, err := measureBFS(tc.problem, nil)
+			require.NoError(t, err)
+			require.Equal(t, len(expected.Steps), it.Len())
+			var steps []*models.Step
+			for step, ok := it.Next(); ok; step, ok = it.Next() {
+				steps = append(steps, step)
+			}
+			require.Equal(t, expected.Steps, steps)
+			_, ok := it.Next()
+			require.False(t, ok)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// treeVersion is the version of the binary encoding of trees.
+const treeVersion = 1
+
+// ErrCorruptTree is returned when decoding an invalid binary tree.
+var ErrCorruptTree = errors.New("corrupt tree")
+
+// treeNode is a state of the shortest-path tree, reached from its parent
+// by the action at the given index of actions.
+type treeNode struct {
+	x      int
+	y      int
+	parent int
+	action int
+}
+
+// Tree is the breadth-first shortest-path tree of every state reachable
+// with a pair of jugs. Once built, the optimal solution for any target of
+// that pair is found by walking the tree, without searching again.
+type Tree struct {
+	xCap  int
+	yCap  int
+	nodes []treeNode // nodes in discovery order, starting at (0,0).
+}
+
+// BuildTree builds the shortest-path tree of jugs of capacities xMax and
+// yMax, discovering states in the same order as bfs does.
+func BuildTree(xMax, yMax int) *Tree {
+	t := &Tree{xCap: xMax, yCap: yMax, nodes: []treeNode{{parent: -1}}}
+	visited := map[[2]int]bool{{0, 0}: true}
+	for i := 0; i < len(t.nodes); i++ {
+		for action := range actions {
+			x, y := move(t.nodes[i].x, t.nodes[i].y, xMax, yMax, action)
+			if !visited[[2]int{x, y}] {
+				visited[[2]int{x, y}] = true
+				t.nodes = append(t.nodes, treeNode{x: x, y: y, parent: i, action: action})
+			}
+		}
+	}
+	return t
+}
+
+// Solve returns the optimal solution to the given problem by walking the
+// tree. It's the same solution bfs finds, and nil when there's none. As the
+// tree covers every reachable state, the optimum is known even when it
+// exceeds the problem's maximum number of steps.
+func (t *Tree) Solve(p Problem) (*models.Solution, error) {
+	if p.XCap != t.xCap || p.YCap != t.yCap {
+		return nil, fmt.Errorf("tree of jugs %d and %d can't solve problem with jugs %d and %d", t.xCap, t.yCap, p.XCap, p.YCap)
+	}
+	if p.Accumulate {
+		return nil, ErrUnsupportedGoal
+	}
+	if len(p.ActionOrder) > 0 {
+		return nil, ErrUnsupportedActionOrder
+	}
+	if !p.feasible() {
+		return p.settle(nil, true)
+	}
+	for i := 1; i < len(t.nodes); i++ {
+		if p.reached(&state{x: t.nodes[i].x, y: t.nodes[i].y}) {
+			goal := t.path(i)
+			goal.status = "Solved"
+			return p.settle(solutionFrom(goal), true)
+		}
+	}
+	return p.settle(nil, true)
+}
+
+// path returns the state of the node at index i, linked to its ancestors.
+func (t *Tree) path(i int) *state {
+	node := t.nodes[i]
+	if node.parent < 0 {
+		return initialState()
+	}
+	return &state{x: node.x, y: node.y, action: actions[node.action], prev: t.path(node.parent)}
+}
+
+// MarshalBinary encodes the tree in a compact binary form. Since states
+// follow from their parent and action, only those are kept: as parents
+// never decrease in discovery order, each node takes a single varint
+// combining the parent's offset from the previous node's parent and the action.
+func (t *Tree) MarshalBinary() ([]byte, error) {
+	data := []byte{treeVersion}
+	data = binary.AppendUvarint(data, uint64(t.xCap))
+	data = binary.AppendUvarint(data, uint64(t.yCap))
+	data = binary.AppendUvarint(data, uint64(len(t.nodes)-1))
+	var prevParent int
+	for _, node := range t.nodes[1:] {
+		data = binary.AppendUvarint(data, uint64(node.parent-prevParent)<<3|uint64(node.action))
+		prevParent = node.parent
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes a tree encoded by MarshalBinary.
+func (t *Tree) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != treeVersion {
+		return fmt.Errorf("%w: unsupported version", ErrCorruptTree)
+	}
+	data = data[1:]
+	readUvarint := func() (uint64, error) {
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			return 0, fmt.Errorf("%w: truncated data", ErrCorruptTree)
+		}
+		data = data[n:]
+		return v, nil
+	}
+	var header [3]uint64
+	for i := range header {
+		v, err := readUvarint()
+		if err != nil {
+			return err
+		}
+		header[i] = v
+	}
+	// bounds are checked before converting to int, which may overflow.
+	if header[0] > math.MaxInt32 || header[1] > math.MaxInt32 {
+		return fmt.Errorf("%w: invalid capacities", ErrCorruptTree)
+	}
+	// each node takes at least one byte.
+	if header[2] > uint64(len(data)) {
+		return fmt.Errorf("%w: truncated data", ErrCorruptTree)
+	}
+	xCap, yCap, count := int(header[0]), int(header[1]), int(header[2])
+	nodes := make([]treeNode, 1, count+1)
+	nodes[0] = treeNode{parent: -1}
+	var prevParent int
+	for i := 1; i <= count; i++ {
+		v, err := readUvarint()
+		if err != nil {
+			return err
+		}
+		if v>>3 >= uint64(i-prevParent) || int(v&7) >= len(actions) {
+			return fmt.Errorf("%w: invalid node %d", ErrCorruptTree, i)
+		}
+		parent, action := prevParent+int(v>>3), int(v&7)
+		x, y := move(nodes[parent].x, nodes[parent].y, xCap, yCap, action)
+		nodes = append(nodes, treeNode{x: x, y: y, parent: parent, action: action})
+		prevParent = parent
+	}
+	if len(data) > 0 {
+		return fmt.Errorf("%w: trailing data", ErrCorruptTree)
+	}
+	t.xCap, t.yCap, t.nodes = xCap, yCap, nodes
+	return nil
+}
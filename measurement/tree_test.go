@@ -0,0 +1,159 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package measurement
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+func TestTreeMatchesBFS(t *testing.T) {
+	goals := []*models.Goal{
+		nil,
+		{X: intPtr(0), Y: intPtr(0)},
+		{X: intPtr(4), Y: intPtr(0)},
+		{AnyOf: []int{2, 7}},
+		{Difference: intPtr(1)},
+	}
+	for xMax := 1; xMax <= 12; xMax++ {
+		for yMax := 1; yMax <= 12; yMax++ {
+			tree := BuildTree(xMax, yMax)
+			data, err := tree.MarshalBinary()
+			require.NoError(t, err)
+			decoded := new(Tree)
+			require.NoError(t, decoded.UnmarshalBinary(data))
+			require.Equal(t, tree, decoded)
+			for target := 1; target <= max(xMax, yMax); target++ {
+				for _, spec := range goals {
+					goal, err := CompileGoal(spec)
+					require.NoError(t, err)
+					p := Problem{XCap: xMax, YCap: yMax, Target: target, Goal: goal}
+					expected := solutionFrom(bfs(p, nil))
+					output, err := decoded.Solve(p)
+					if expected == nil {
+						require.ErrorIsf(t, err, ErrNoSolution, "x=%d y=%d z=%d goal=%v", xMax, yMax, target, spec)
+						continue
+					}
+					require.NoError(t, err)
+					require.Equalf(t, expected, output, "x=%d y=%d z=%d goal=%v", xMax, yMax, target, spec)
+				}
+			}
+		}
+	}
+}
+
+func TestTreeSolveKnowsOptimum(t *testing.T) {
+	six := 6
+	_, err := BuildTree(3, 5).Solve(Problem{XCap: 3, YCap: 5, Target: 4, MaxSteps: 5})
+	require.Equal(t, &NoSolutionError{Reason: ReasonBudgetExhausted, XCap: 3, YCap: 5, Target: 4, MaxSteps: 5, Optimum: &six}, err)
+	require.EqualError(t, err, "no solution within 5 steps: the optimal solution takes 6")
+}
+
+func TestTreeSolveMismatchedJugs(t *testing.T) {
+	_, err := BuildTree(3, 5).Solve(Problem{XCap: 5, YCap: 3, Target: 4})
+	require.EqualError(t, err, "tree of jugs 3 and 5 can't solve problem with jugs 5 and 3")
+}
+
+func TestTreeUnmarshalBinary(t *testing.T) {
+	data, err := BuildTree(3, 5).MarshalBinary()
+	require.NoError(t, err)
+	testCases := []struct {
+		name          string
+		data          []byte
+		expectedError error
+	}{
+		{
+			name: "happy path",
+			data: data,
+		},
+		{
+			name:          "empty data",
+			expectedError: errors.New("corrupt tree: unsupported version"),
+		},
+		{
+			name:          "unsupported version",
+			data:          append([]byte{9}, data[1:]...),
+			expectedError: errors.New("corrupt tree: unsupported version"),
+		},
+		{
+			name:          "truncated data",
+			data:          data[:len(data)-1],
+			expectedError: errors.New("corrupt tree: truncated data"),
+		},
+		{
+			name:          "trailing data",
+			data:          append(append([]byte{}, data...), 0),
+			expectedError: errors.New("corrupt tree: trailing data"),
+		},
+		{
+			name:          "invalid node",
+			data:          []byte{treeVersion, 3, 5, 1, 1<<3 | 0},
+			expectedError: errors.New("corrupt tree: invalid node 1"),
+		},
+		{
+			name:          "node count overflowing int",
+			data:          binary.AppendUvarint([]byte{treeVersion, 3, 5}, math.MaxUint64),
+			expectedError: errors.New("corrupt tree: truncated data"),
+		},
+		{
+			name:          "capacity overflowing int",
+			data:          append(binary.AppendUvarint([]byte{treeVersion}, math.MaxUint64), 5, 0),
+			expectedError: errors.New("corrupt tree: invalid capacities"),
+		},
+		{
+			name:          "parent offset overflowing int",
+			data:          binary.AppendUvarint([]byte{treeVersion, 3, 5, 1}, math.MaxUint64),
+			expectedError: errors.New("corrupt tree: invalid node 1"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := new(Tree).UnmarshalBinary(tc.data)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error to occur, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error to occur, got nil`)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkTree(b *testing.B) {
+	const xMax, yMax = 997, 1009
+	b.Run("bfs/every target", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for target := 1; target <= yMax; target += 100 {
+				_, _ = Measure(xMax, yMax, target)
+			}
+		}
+	})
+	tree := BuildTree(xMax, yMax)
+	data, err := tree.MarshalBinary()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run(fmt.Sprintf("tree/every target/%d bytes", len(data)), func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			decoded := new(Tree)
+			if err := decoded.UnmarshalBinary(data); err != nil {
+				b.Fatal(err)
+			}
+			for target := 1; target <= yMax; target += 100 {
+				decoded.Solve(Problem{XCap: xMax, YCap: yMax, Target: target})
+			}
+		}
+	})
+}
@@ -7,9 +7,11 @@ package middleware
 import (
 	"log/slog"
 	"net/http"
+	"runtime/debug"
 	"time"
 
 	"github.com/gorilla/handlers"
+	"github.com/tiagomelo/golang-waterjug-api/web"
 )
 
 // Logger is a middleware that logs the start and end of each HTTP request along with
@@ -38,7 +40,21 @@ func Compress(next http.Handler) http.Handler {
 }
 
 // PanicRecovery is a middleware that recovers from panics in the application,
-// preventing the server from crashing and logging the stack trace.
-func PanicRecovery(next http.Handler) http.Handler {
-	return handlers.RecoveryHandler(handlers.PrintRecoveryStack(true))(next)
+// preventing the server from crashing, logging the stack trace and responding
+// with an internal error problem.
+func PanicRecovery(log *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				log.Error("request panicked",
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+					slog.Any("panic", rec),
+					slog.String("stack", string(debug.Stack())),
+				)
+				web.RespondWithError(w, r, http.StatusInternalServerError, nil)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
 }
@@ -0,0 +1,76 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package puzzle
+
+import (
+	"time"
+
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// DateLayout is the layout of the dates identifying daily puzzles.
+const DateLayout = "2006-01-02"
+
+// Capacity range of the jugs used in daily puzzles.
+const (
+	dailyMinCapacity = 3
+	dailyMaxCapacity = 30
+)
+
+// Difficulty levels of daily puzzles.
+const (
+	DifficultyEasy   = "easy"
+	DifficultyMedium = "medium"
+	DifficultyHard   = "hard"
+)
+
+// dailySteps maps each weekday to the optimal number of steps of its
+// puzzle, so that puzzles get harder as the week goes by. Optimal
+// solutions longer than one step always have an even number of steps.
+var dailySteps = map[time.Weekday]int{
+	time.Monday:    4,
+	time.Tuesday:   6,
+	time.Wednesday: 8,
+	time.Thursday:  10,
+	time.Friday:    12,
+	time.Saturday:  14,
+	time.Sunday:    16,
+}
+
+// difficulty returns the difficulty level of a puzzle with the given
+// optimal number of steps.
+func difficulty(steps int) string {
+	switch {
+	case steps <= 6:
+		return DifficultyEasy
+	case steps <= 10:
+		return DifficultyMedium
+	default:
+		return DifficultyHard
+	}
+}
+
+// Daily returns the puzzle of the given date. The puzzle only depends on
+// the date, so every caller gets the same one; its difficulty grows from
+// Monday to Sunday.
+func Daily(date time.Time) (*models.DailyPuzzle, error) {
+	seed := int64(date.Year()*10000 + int(date.Month())*100 + date.Day())
+	steps := dailySteps[date.Weekday()]
+	generated, err := Generate(&models.NewPuzzles{
+		Steps:       steps,
+		MinCapacity: dailyMinCapacity,
+		MaxCapacity: dailyMaxCapacity,
+		Count:       1,
+		Seed:        &seed,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &models.DailyPuzzle{
+		Date:       date.Format(DateLayout),
+		Difficulty: difficulty(steps),
+		Puzzle:     *generated.Puzzles[0],
+	}, nil
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package puzzle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/measurement"
+)
+
+func TestDaily(t *testing.T) {
+	testCases := []struct {
+		name               string
+		date               time.Time
+		expectedSteps      int
+		expectedDifficulty string
+	}{
+		{
+			name:               "monday",
+			date:               time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+			expectedSteps:      4,
+			expectedDifficulty: DifficultyEasy,
+		},
+		{
+			name:               "thursday",
+			date:               time.Date(2024, time.January, 4, 0, 0, 0, 0, time.UTC),
+			expectedSteps:      10,
+			expectedDifficulty: DifficultyMedium,
+		},
+		{
+			name:               "sunday",
+			date:               time.Date(2024, time.January, 7, 0, 0, 0, 0, time.UTC),
+			expectedSteps:      16,
+			expectedDifficulty: DifficultyHard,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := Daily(tc.date)
+			require.NoError(t, err)
+			require.Equal(t, tc.date.Format(DateLayout), output.Date)
+			require.Equal(t, tc.expectedSteps, output.Steps)
+			require.Equal(t, tc.expectedDifficulty, output.Difficulty)
+			solution, err := measurement.Measure(output.XCap, output.YCap, output.ZAmountWanted)
+			require.NoError(t, err)
+			require.Len(t, solution.Steps, tc.expectedSteps)
+			again, err := Daily(tc.date.Add(23 * time.Hour))
+			require.NoError(t, err)
+			require.Equal(t, output, again)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package puzzle
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/tiagomelo/golang-waterjug-api/measurement"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// maxAttempts is the number of jug pairs tried before giving up.
+const maxAttempts = 1000
+
+// ErrNotEnoughPuzzles is returned when the requested puzzles can't be found.
+var ErrNotEnoughPuzzles = errors.New("not enough puzzles")
+
+// For ease of unit testing.
+var (
+	// now returns the current time, used to seed the generation when no seed is given.
+	now = time.Now
+)
+
+// pairTables holds what's known about the targets of a jug pair.
+type pairTables struct {
+	minSteps  map[int]int // minSteps holds the length of the optimal solutions of every target.
+	solutions map[int]int // solutions holds the number of optimal solutions of every target.
+}
+
+// Generate generates distinct puzzles whose optimal solution, as found by
+// measurement.Measure, has exactly spec.Steps steps, with jug capacities
+// within [spec.MinCapacity, spec.MaxCapacity]. Puzzles that only differ by
+// swapping the jugs are considered the same. Every puzzle is:
+//
+//   - unique: its optimal solution is the only sequence of spec.Steps actions
+//     measuring the target, so it's the one answer to the puzzle;
+//   - non-trivial: both jugs have different capacities, neither of which is
+//     a multiple of the other, and the target takes more than a single step.
+//
+// Generation is reproducible through spec.Seed.
+func Generate(spec *models.NewPuzzles) (*models.GeneratedPuzzles, error) {
+	seed := now().UnixNano()
+	if spec.Seed != nil {
+		seed = *spec.Seed
+	}
+	count := spec.Count
+	if count == 0 {
+		count = 1
+	}
+	rng := rand.New(rand.NewSource(seed))
+	seen := make(map[[3]int]bool)
+	// tables holds the tables of every jug pair tried so far,
+	// since small capacity ranges yield the same pairs over and over.
+	tables := make(map[[2]int]*pairTables)
+	generated := &models.GeneratedPuzzles{Seed: seed, Puzzles: []*models.Puzzle{}}
+	for attempt := 0; attempt < maxAttempts && len(generated.Puzzles) < count; attempt++ {
+		xCap := spec.MinCapacity + rng.Intn(spec.MaxCapacity-spec.MinCapacity+1)
+		yCap := spec.MinCapacity + rng.Intn(spec.MaxCapacity-spec.MinCapacity+1)
+		if xCap%yCap == 0 || yCap%xCap == 0 {
+			continue
+		}
+		pair := [2]int{min(xCap, yCap), max(xCap, yCap)}
+		if _, ok := tables[pair]; !ok {
+			tables[pair] = &pairTables{
+				minSteps:  measurement.MinSteps(pair[0], pair[1]),
+				solutions: measurement.OptimalSolutions(pair[0], pair[1]),
+			}
+		}
+		var candidates []int
+		for target, steps := range tables[pair].minSteps {
+			if steps == spec.Steps && tables[pair].solutions[target] == 1 && !seen[[3]int{pair[0], pair[1], target}] {
+				candidates = append(candidates, target)
+			}
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+		// map iteration order is random, so candidates are sorted
+		// to keep the generation reproducible.
+		sort.Ints(candidates)
+		target := candidates[rng.Intn(len(candidates))]
+		seen[[3]int{pair[0], pair[1], target}] = true
+		generated.Puzzles = append(generated.Puzzles, &models.Puzzle{
+			XCap:          xCap,
+			YCap:          yCap,
+			ZAmountWanted: target,
+			Steps:         spec.Steps,
+		})
+	}
+	if len(generated.Puzzles) < count {
+		return nil, fmt.Errorf("%w: found %d of %d with %d steps and capacities between %d and %d", ErrNotEnoughPuzzles,
+			len(generated.Puzzles), count, spec.Steps, spec.MinCapacity, spec.MaxCapacity)
+	}
+	return generated, nil
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package puzzle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/measurement"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
+
+func TestGenerate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		spec          *models.NewPuzzles
+		mockNow       func() time.Time
+		expectedSeed  int64
+		expectedError error
+	}{
+		{
+			name:         "happy path",
+			spec:         &models.NewPuzzles{Steps: 6, MinCapacity: 3, MaxCapacity: 20, Count: 5, Seed: int64Ptr(42)},
+			expectedSeed: 42,
+		},
+		{
+			name:         "every unique puzzle",
+			spec:         &models.NewPuzzles{Steps: 10, MinCapacity: 3, MaxCapacity: 8, Count: 4, Seed: int64Ptr(42)},
+			expectedSeed: 42,
+		},
+		{
+			name:         "seed from current time",
+			spec:         &models.NewPuzzles{Steps: 2, MinCapacity: 2, MaxCapacity: 5},
+			mockNow:      func() time.Time { return time.Unix(0, 7) },
+			expectedSeed: 7,
+		},
+		{
+			// (3, 8, 4) also takes 10 steps, but has two optimal solutions.
+			name:          "puzzles with several optimal solutions left out",
+			spec:          &models.NewPuzzles{Steps: 10, MinCapacity: 3, MaxCapacity: 8, Count: 5, Seed: int64Ptr(42)},
+			expectedError: ErrNotEnoughPuzzles,
+		},
+		{
+			name:          "not enough puzzles",
+			spec:          &models.NewPuzzles{Steps: 50, MinCapacity: 1, MaxCapacity: 3, Seed: int64Ptr(42)},
+			expectedError: ErrNotEnoughPuzzles,
+		},
+	}
+	originalNow := now
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				now = originalNow
+			}()
+			if tc.mockNow != nil {
+				now = tc.mockNow
+			}
+			output, err := Generate(tc.spec)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error to occur, got "%v"`, err)
+				}
+				require.ErrorIs(t, err, tc.expectedError)
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error to occur, got nil`)
+				}
+				require.Equal(t, tc.expectedSeed, output.Seed)
+				require.Len(t, output.Puzzles, max(1, tc.spec.Count))
+				seen := make(map[[3]int]bool)
+				for _, p := range output.Puzzles {
+					require.NotZero(t, max(p.XCap, p.YCap)%min(p.XCap, p.YCap), "capacities multiple of each other %d, %d", p.XCap, p.YCap)
+					require.GreaterOrEqual(t, p.XCap, tc.spec.MinCapacity)
+					require.LessOrEqual(t, p.YCap, tc.spec.MaxCapacity)
+					key := [3]int{min(p.XCap, p.YCap), max(p.XCap, p.YCap), p.ZAmountWanted}
+					require.False(t, seen[key], "duplicated puzzle %v", key)
+					seen[key] = true
+					solution, err := measurement.Measure(p.XCap, p.YCap, p.ZAmountWanted)
+					require.NoError(t, err)
+					require.Len(t, solution.Steps, tc.spec.Steps)
+					require.Equal(t, 1, measurement.OptimalSolutions(p.XCap, p.YCap)[p.ZAmountWanted], "puzzle %v has several optimal solutions", key)
+				}
+				again, err := Generate(&models.NewPuzzles{
+					Steps:       tc.spec.Steps,
+					MinCapacity: tc.spec.MinCapacity,
+					MaxCapacity: tc.spec.MaxCapacity,
+					Count:       tc.spec.Count,
+					Seed:        int64Ptr(output.Seed),
+				})
+				require.NoError(t, err)
+				require.Equal(t, output, again)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// asciiHeight is the height, in lines, of the largest jug.
+const asciiHeight = 5
+
+// ASCII writes a terminal-friendly picture of both jugs for every step of
+// the solution. Jug heights are proportional to their capacities.
+func ASCII(w io.Writer, solution *models.Solution, xCap, yCap int) error {
+	bw := bufio.NewWriter(w)
+	maxCap := max(xCap, yCap)
+	xRows, yRows := scale(xCap, maxCap, asciiHeight), scale(yCap, maxCap, asciiHeight)
+	for i, f := range frames(solution) {
+		if i > 0 {
+			fmt.Fprintln(bw)
+		}
+		title := fmt.Sprintf("Step %d: %s", f.number, f.action)
+		if f.number == 0 {
+			title = f.action
+		}
+		if f.solved {
+			title += " (Solved)"
+		}
+		fmt.Fprintln(bw, title)
+		xFilled, yFilled := scale(f.bucketX, xCap, xRows), scale(f.bucketY, yCap, yRows)
+		for row := asciiHeight - 1; row >= 0; row-- {
+			line := asciiJugRow(row, xRows, xFilled) + "  " + asciiJugRow(row, yRows, yFilled)
+			fmt.Fprintln(bw, strings.TrimRight(line, " "))
+		}
+		fmt.Fprintln(bw, "+-----+  +-----+")
+		fmt.Fprintf(bw, "%-9s%s\n", fmt.Sprintf("X %d/%d", f.bucketX, xCap), fmt.Sprintf("Y %d/%d", f.bucketY, yCap))
+	}
+	if err := bw.Flush(); err != nil {
+		return errors.Wrap(err, "writing ASCII solution")
+	}
+	return nil
+}
+
+// asciiJugRow draws a single line of a jug with the given number of rows,
+// of which the lowest filled ones hold water.
+func asciiJugRow(row, rows, filled int) string {
+	switch {
+	case row >= rows:
+		return "       "
+	case row < filled:
+		return "|~~~~~|"
+	}
+	return "|     |"
+}
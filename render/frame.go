@@ -0,0 +1,73 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package render
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// MaxRenderedSteps is the largest number of steps of a solution rendered as
+// an animated SVG or a PNG contact sheet. The size of both grows faster than
+// the number of steps: every frame of an SVG animates through all of them,
+// and a contact sheet is a single image holding them all.
+const MaxRenderedSteps = 200
+
+// ErrTooManySteps is returned when rendering a solution with more than
+// MaxRenderedSteps steps as an image.
+var ErrTooManySteps = errors.New("too many steps to render")
+
+// checkSteps returns an error when the solution is too long to be rendered
+// as an image.
+func checkSteps(solution *models.Solution) error {
+	if len(solution.Steps) > MaxRenderedSteps {
+		return fmt.Errorf("%w: the solution has %d steps, more than the limit of %d", ErrTooManySteps, len(solution.Steps), MaxRenderedSteps)
+	}
+	return nil
+}
+
+// frame represents the jugs at a given point of the solution.
+type frame struct {
+	number  int
+	bucketX int
+	bucketY int
+	action  string
+	solved  bool
+}
+
+// frames returns one frame per step of the solution, preceded by the
+// initial frame where both jugs are empty.
+func frames(solution *models.Solution) []frame {
+	f := []frame{{action: "Start"}}
+	for _, step := range solution.Steps {
+		f = append(f, frame{
+			number:  step.Number,
+			bucketX: step.BucketX,
+			bucketY: step.BucketY,
+			action:  step.Action,
+			solved:  step.Status == "Solved",
+		})
+	}
+	return f
+}
+
+// scale returns amount scaled from [0, capacity] to [0, size], rounding
+// to the nearest integer but never hiding a partially filled jug as
+// empty or full.
+func scale(amount, capacity, size int) int {
+	if capacity == 0 {
+		return 0
+	}
+	scaled := (amount*size + capacity/2) / capacity
+	switch {
+	case amount > 0 && scaled == 0:
+		return 1
+	case amount < capacity && scaled == size && size > 1:
+		return size - 1
+	}
+	return scaled
+}
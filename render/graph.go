@@ -0,0 +1,135 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package render
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// DOT writes the state graph in Graphviz DOT format.
+// Nodes and edges on the highlighted path are drawn in red.
+func DOT(w io.Writer, graph *models.Graph) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "digraph jugs {\n")
+	fmt.Fprintf(bw, "  label=%q;\n", fmt.Sprintf("jugs of capacities %d and %d", graph.XCap, graph.YCap))
+	for _, node := range graph.Nodes {
+		attrs := fmt.Sprintf("label=%q", fmt.Sprintf("(%d,%d)", node.BucketX, node.BucketY))
+		if node.Initial {
+			attrs += ", shape=doublecircle"
+		}
+		if node.OnPath {
+			attrs += ", color=red, penwidth=2"
+		}
+		fmt.Fprintf(bw, "  %q [%s];\n", node.ID, attrs)
+	}
+	for _, edge := range graph.Edges {
+		attrs := fmt.Sprintf("label=%q", edge.Action)
+		if edge.OnPath {
+			attrs += ", color=red, penwidth=2"
+		}
+		fmt.Fprintf(bw, "  %q -> %q [%s];\n", edge.From, edge.To, attrs)
+	}
+	fmt.Fprintf(bw, "}\n")
+	if err := bw.Flush(); err != nil {
+		return errors.Wrap(err, "writing DOT graph")
+	}
+	return nil
+}
+
+// graphMLKey declares a GraphML attribute.
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+// graphMLData holds the value of a GraphML attribute.
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// graphMLNode represents a GraphML node.
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+// graphMLEdge represents a GraphML edge.
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+// graphMLGraph represents a GraphML graph.
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+// graphMLDocument represents a GraphML document.
+type graphMLDocument struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+// GraphML writes the state graph in GraphML format.
+func GraphML(w io.Writer, graph *models.Graph) error {
+	doc := graphMLDocument{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "bucketX", For: "node", AttrName: "bucketX", AttrType: "int"},
+			{ID: "bucketY", For: "node", AttrName: "bucketY", AttrType: "int"},
+			{ID: "initial", For: "node", AttrName: "initial", AttrType: "boolean"},
+			{ID: "nodeOnPath", For: "node", AttrName: "onPath", AttrType: "boolean"},
+			{ID: "action", For: "edge", AttrName: "action", AttrType: "string"},
+			{ID: "edgeOnPath", For: "edge", AttrName: "onPath", AttrType: "boolean"},
+		},
+		Graph: graphMLGraph{ID: "jugs", EdgeDefault: "directed"},
+	}
+	for _, node := range graph.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: node.ID,
+			Data: []graphMLData{
+				{Key: "bucketX", Value: strconv.Itoa(node.BucketX)},
+				{Key: "bucketY", Value: strconv.Itoa(node.BucketY)},
+				{Key: "initial", Value: strconv.FormatBool(node.Initial)},
+				{Key: "nodeOnPath", Value: strconv.FormatBool(node.OnPath)},
+			},
+		})
+	}
+	for _, edge := range graph.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			Source: edge.From,
+			Target: edge.To,
+			Data: []graphMLData{
+				{Key: "action", Value: edge.Action},
+				{Key: "edgeOnPath", Value: strconv.FormatBool(edge.OnPath)},
+			},
+		})
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return errors.Wrap(err, "writing GraphML header")
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return errors.Wrap(err, "encoding GraphML graph")
+	}
+	return nil
+}
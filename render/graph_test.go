@@ -0,0 +1,78 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package render
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// testGraph is a graph with a single highlighted action.
+var testGraph = &models.Graph{
+	XCap: 1,
+	YCap: 1,
+	Nodes: []*models.GraphNode{
+		{ID: "0,0", Initial: true, OnPath: true},
+		{ID: "1,0", BucketX: 1, OnPath: true},
+	},
+	Edges: []*models.GraphEdge{
+		{From: "0,0", To: "1,0", Action: "Fill bucket X", OnPath: true},
+		{From: "1,0", To: "0,0", Action: "Empty bucket X"},
+	},
+}
+
+func TestDOT(t *testing.T) {
+	expectedOutput := `digraph jugs {
+  label="jugs of capacities 1 and 1";
+  "0,0" [label="(0,0)", shape=doublecircle, color=red, penwidth=2];
+  "1,0" [label="(1,0)", color=red, penwidth=2];
+  "0,0" -> "1,0" [label="Fill bucket X", color=red, penwidth=2];
+  "1,0" -> "0,0" [label="Empty bucket X"];
+}
+`
+	var buf bytes.Buffer
+	require.NoError(t, DOT(&buf, testGraph))
+	require.Equal(t, expectedOutput, buf.String())
+}
+
+func TestGraphML(t *testing.T) {
+	expectedOutput := `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="bucketX" for="node" attr.name="bucketX" attr.type="int"></key>
+  <key id="bucketY" for="node" attr.name="bucketY" attr.type="int"></key>
+  <key id="initial" for="node" attr.name="initial" attr.type="boolean"></key>
+  <key id="nodeOnPath" for="node" attr.name="onPath" attr.type="boolean"></key>
+  <key id="action" for="edge" attr.name="action" attr.type="string"></key>
+  <key id="edgeOnPath" for="edge" attr.name="onPath" attr.type="boolean"></key>
+  <graph id="jugs" edgedefault="directed">
+    <node id="0,0">
+      <data key="bucketX">0</data>
+      <data key="bucketY">0</data>
+      <data key="initial">true</data>
+      <data key="nodeOnPath">true</data>
+    </node>
+    <node id="1,0">
+      <data key="bucketX">1</data>
+      <data key="bucketY">0</data>
+      <data key="initial">false</data>
+      <data key="nodeOnPath">true</data>
+    </node>
+    <edge source="0,0" target="1,0">
+      <data key="action">Fill bucket X</data>
+      <data key="edgeOnPath">true</data>
+    </edge>
+    <edge source="1,0" target="0,0">
+      <data key="action">Empty bucket X</data>
+      <data key="edgeOnPath">false</data>
+    </edge>
+  </graph>
+</graphml>`
+	var buf bytes.Buffer
+	require.NoError(t, GraphML(&buf, testGraph))
+	require.Equal(t, expectedOutput, buf.String())
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// contactSheetColumns is the number of frames per row of the contact sheet.
+const contactSheetColumns = 5
+
+// Colors used in the contact sheet.
+var (
+	backgroundColor = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	waterColor      = color.RGBA{R: 0x3b, G: 0x82, B: 0xf6, A: 0xff}
+	outlineColor    = color.RGBA{A: 0xff}
+	solvedColor     = color.RGBA{R: 0x16, G: 0xa3, B: 0x4a, A: 0xff}
+	frameColor      = color.RGBA{R: 0xd1, G: 0xd5, B: 0xdb, A: 0xff}
+)
+
+// PNG writes a contact sheet of the solution: a grid of frames, read left to
+// right and top to bottom, each showing both jugs after a step. The first
+// frame shows the empty jugs and the frame that solves the problem has a green border.
+// Solutions with more than MaxRenderedSteps steps are refused with ErrTooManySteps.
+func PNG(w io.Writer, solution *models.Solution, xCap, yCap int) error {
+	if err := checkSteps(solution); err != nil {
+		return err
+	}
+	f := frames(solution)
+	maxCap := max(xCap, yCap)
+	frameWidth := 2*margin + 2*jugWidth + jugGap
+	frameHeight := 2*margin + jugMaxHeight
+	columns := min(len(f), contactSheetColumns)
+	rows := (len(f) + columns - 1) / columns
+	img := image.NewRGBA(image.Rect(0, 0, columns*frameWidth, rows*frameHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: backgroundColor}, image.Point{}, draw.Src)
+	for i, fr := range f {
+		origin := image.Pt((i%columns)*frameWidth, (i/columns)*frameHeight)
+		bottom := origin.Y + margin + jugMaxHeight
+		for j, jug := range [][2]int{{fr.bucketX, xCap}, {fr.bucketY, yCap}} {
+			x := origin.X + margin + j*(jugWidth+jugGap)
+			jugHeight := scale(jug[1], maxCap, jugMaxHeight)
+			water := scale(jug[0], jug[1], jugHeight)
+			draw.Draw(img, image.Rect(x, bottom-water, x+jugWidth, bottom), &image.Uniform{C: waterColor}, image.Point{}, draw.Src)
+			outline(img, image.Rect(x, bottom-jugHeight, x+jugWidth, bottom), outlineColor)
+		}
+		border := frameColor
+		if fr.solved {
+			border = solvedColor
+		}
+		outline(img, image.Rect(origin.X+4, origin.Y+4, origin.X+frameWidth-4, origin.Y+frameHeight-4), border)
+	}
+	if err := png.Encode(w, img); err != nil {
+		return errors.Wrap(err, "encoding PNG contact sheet")
+	}
+	return nil
+}
+
+// outline draws a two pixels wide border around the given rectangle.
+func outline(img draw.Image, r image.Rectangle, c color.Color) {
+	u := &image.Uniform{C: c}
+	draw.Draw(img, image.Rect(r.Min.X-2, r.Min.Y-2, r.Max.X+2, r.Min.Y), u, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(r.Min.X-2, r.Max.Y, r.Max.X+2, r.Max.Y+2), u, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(r.Min.X-2, r.Min.Y, r.Min.X, r.Max.Y), u, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(r.Max.X, r.Min.Y, r.Max.X+2, r.Max.Y), u, image.Point{}, draw.Src)
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package render
+
+import (
+	"bytes"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// testSolution measures 1 litre with jugs of capacities 2 and 3.
+var testSolution = &models.Solution{
+	Steps: []*models.Step{
+		{Number: 1, BucketX: 0, BucketY: 3, Action: "Fill bucket Y"},
+		{Number: 2, BucketX: 2, BucketY: 1, Action: "Transfer from bucket Y to X", Status: "Solved"},
+	},
+}
+
+func TestASCII(t *testing.T) {
+	expectedOutput := `Start
+         |     |
+         |     |
+|     |  |     |
+|     |  |     |
+|     |  |     |
++-----+  +-----+
+X 0/2    Y 0/3
+
+Step 1: Fill bucket Y
+         |~~~~~|
+         |~~~~~|
+|     |  |~~~~~|
+|     |  |~~~~~|
+|     |  |~~~~~|
++-----+  +-----+
+X 0/2    Y 3/3
+
+Step 2: Transfer from bucket Y to X (Solved)
+         |     |
+         |     |
+|~~~~~|  |     |
+|~~~~~|  |~~~~~|
+|~~~~~|  |~~~~~|
++-----+  +-----+
+X 2/2    Y 1/3
+`
+	var buf bytes.Buffer
+	require.NoError(t, ASCII(&buf, testSolution, 2, 3))
+	require.Equal(t, expectedOutput, buf.String())
+}
+
+func TestSVG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, SVG(&buf, testSolution, 2, 3))
+	output := buf.String()
+	require.True(t, strings.HasPrefix(output, `<svg xmlns="http://www.w3.org/2000/svg" width="200" height="200" viewBox="0 0 200 200">`))
+	require.Contains(t, output, `<animate attributeName="height" values="0;0;80" keyTimes="0.0000;0.3333;0.6667" dur="3s" calcMode="discrete" repeatCount="indefinite"/>`)
+	require.Contains(t, output, `>Step 2: Transfer from bucket Y to X (Solved)<animate attributeName="opacity" values="0;0;1"`)
+	require.True(t, strings.HasSuffix(output, "</svg>\n"))
+}
+
+func TestPNG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, PNG(&buf, testSolution, 2, 3))
+	img, err := png.Decode(&buf)
+	require.NoError(t, err)
+	require.Equal(t, 600, img.Bounds().Dx())
+	require.Equal(t, 160, img.Bounds().Dy())
+}
+
+// solutionOf returns a solution with the given number of steps, alternately
+// filling and emptying jug X.
+func solutionOf(steps int) *models.Solution {
+	solution := &models.Solution{}
+	for i := 1; i <= steps; i++ {
+		step := &models.Step{Number: i, Action: "Empty bucket X"}
+		if i%2 == 1 {
+			step.BucketX, step.Action = 2, "Fill bucket X"
+		}
+		solution.Steps = append(solution.Steps, step)
+	}
+	solution.Steps[steps-1].Status = "Solved"
+	return solution
+}
+
+func TestRenderedStepsLimit(t *testing.T) {
+	renderers := map[string]func(w io.Writer, solution *models.Solution, xCap, yCap int) error{
+		"svg": SVG,
+		"png": PNG,
+	}
+	for name, render := range renderers {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, render(&buf, solutionOf(MaxRenderedSteps), 2, 3))
+			require.NotZero(t, buf.Len())
+			buf.Reset()
+			err := render(&buf, solutionOf(MaxRenderedSteps+1), 2, 3)
+			require.ErrorIs(t, err, ErrTooManySteps)
+			require.EqualError(t, err, "too many steps to render: the solution has 201 steps, more than the limit of 200")
+			require.Zero(t, buf.Len())
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// Dimensions, in pixels, of the drawings of the jugs.
+const (
+	jugWidth     = 60
+	jugMaxHeight = 120
+	jugGap       = 40
+	margin       = 20
+	captionSize  = 40
+)
+
+// svgFrameDuration is how long each frame of the animation is shown, in seconds.
+const svgFrameDuration = 1
+
+// visibleAt returns the opacity of an element, per frame, that is only
+// visible in the given frame.
+func visibleAt(frame, frames int) []string {
+	opacity := make([]string, frames)
+	for i := range opacity {
+		opacity[i] = "0"
+	}
+	opacity[frame] = "1"
+	return opacity
+}
+
+// SVG writes an animated SVG of the solution, showing one frame per step
+// and looping indefinitely. Solutions with more than MaxRenderedSteps steps
+// are refused with ErrTooManySteps.
+func SVG(w io.Writer, solution *models.Solution, xCap, yCap int) error {
+	if err := checkSteps(solution); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	f := frames(solution)
+	maxCap := max(xCap, yCap)
+	width := 2*margin + 2*jugWidth + jugGap
+	height := 2*margin + jugMaxHeight + captionSize
+	dur := len(f) * svgFrameDuration
+	keyTimes := make([]string, len(f))
+	for i := range f {
+		keyTimes[i] = fmt.Sprintf("%.4f", float64(i)/float64(len(f)))
+	}
+	// animate returns an animation of the attribute through the given values, one per frame.
+	animate := func(attribute string, values []string) string {
+		return fmt.Sprintf(`<animate attributeName="%s" values="%s" keyTimes="%s" dur="%ds" calcMode="discrete" repeatCount="indefinite"/>`,
+			attribute, strings.Join(values, ";"), strings.Join(keyTimes, ";"), dur)
+	}
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", width, height, width, height)
+	fmt.Fprintf(bw, `  <rect width="%d" height="%d" fill="white"/>`+"\n", width, height)
+	jugs := []struct {
+		name     string
+		capacity int
+		amount   func(frame) int
+	}{
+		{name: "X", capacity: xCap, amount: func(f frame) int { return f.bucketX }},
+		{name: "Y", capacity: yCap, amount: func(f frame) int { return f.bucketY }},
+	}
+	bottom := margin + jugMaxHeight
+	for i, jug := range jugs {
+		x := margin + i*(jugWidth+jugGap)
+		jugHeight := scale(jug.capacity, maxCap, jugMaxHeight)
+		var ys, heights, labels []string
+		for _, fr := range f {
+			water := scale(jug.amount(fr), jug.capacity, jugHeight)
+			ys = append(ys, fmt.Sprint(bottom-water))
+			heights = append(heights, fmt.Sprint(water))
+			labels = append(labels, fmt.Sprintf("%s %d/%d", jug.name, jug.amount(fr), jug.capacity))
+		}
+		fmt.Fprintf(bw, `  <rect x="%d" y="%s" width="%d" height="%s" fill="#3b82f6">`+"\n", x, ys[0], jugWidth, heights[0])
+		fmt.Fprintf(bw, "    %s\n    %s\n  </rect>\n", animate("y", ys), animate("height", heights))
+		fmt.Fprintf(bw, `  <rect x="%d" y="%d" width="%d" height="%d" fill="none" stroke="black" stroke-width="2"/>`+"\n",
+			x, bottom-jugHeight, jugWidth, jugHeight)
+		for j, label := range labels {
+			opacity := visibleAt(j, len(f))
+			fmt.Fprintf(bw, `  <text x="%d" y="%d" font-family="monospace" font-size="12" text-anchor="middle" opacity="%s">%s%s</text>`+"\n",
+				x+jugWidth/2, bottom+16, opacity[0], html.EscapeString(label), animate("opacity", opacity))
+		}
+	}
+	for j, fr := range f {
+		caption := fmt.Sprintf("Step %d: %s", fr.number, fr.action)
+		if fr.number == 0 {
+			caption = fr.action
+		}
+		if fr.solved {
+			caption += " (Solved)"
+		}
+		opacity := visibleAt(j, len(f))
+		fmt.Fprintf(bw, `  <text x="%d" y="%d" font-family="monospace" font-size="11" text-anchor="middle" opacity="%s">%s%s</text>`+"\n",
+			width/2, bottom+34, opacity[0], html.EscapeString(caption), animate("opacity", opacity))
+	}
+	fmt.Fprintln(bw, "</svg>")
+	if err := bw.Flush(); err != nil {
+		return errors.Wrap(err, "writing SVG solution")
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package render
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// CSV writes the steps table as CSV, with a "target,steps" header.
+// Targets that cannot be measured have no steps.
+func CSV(w io.Writer, table *models.StepsTable) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"target", "steps"})
+	for _, targetSteps := range table.Targets {
+		var steps string
+		if targetSteps.Solvable {
+			steps = strconv.Itoa(targetSteps.Steps)
+		}
+		cw.Write([]string{strconv.Itoa(targetSteps.Target), steps})
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return errors.Wrap(err, "writing CSV table")
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package render
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+func TestCSV(t *testing.T) {
+	table := &models.StepsTable{
+		XCap: 2,
+		YCap: 4,
+		Targets: []*models.TargetSteps{
+			{Target: 1},
+			{Target: 2, Solvable: true, Steps: 1},
+		},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, CSV(&buf, table))
+	require.Equal(t, "target,steps\n1,\n2,1\n", buf.String())
+}
@@ -8,12 +8,15 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/go-playground/locales/en"
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
 	en_translations "github.com/go-playground/validator/v10/translations/en"
+	"github.com/tiagomelo/golang-waterjug-api/measurement"
 	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
 )
 
@@ -28,6 +31,22 @@ const (
 	splitCount = 2
 
 	lessThanXAndYCapacitiesStructTagName = "less_than_x_y_cap"
+
+	strategyTagName = "strategy"
+
+	requiredWithoutTagName = "required_without"
+
+	greaterThanMinCapacityTagName = "gt_min_capacity"
+
+	excludedWithTagName = "excluded_with"
+
+	actionTagName = "action"
+
+	httpURLTagName = "http_url"
+
+	requiredWithTagName = "required_with"
+
+	inventoryCapacityTagName = "inventory_capacity"
 )
 
 // registerTranslationForLessThanXAndYCapacitiesStructTagName registers custom translation message
@@ -43,6 +62,121 @@ func translationForLessThanXAndYCapacitiesStructTagName(ut ut.Translator, fe val
 	return t
 }
 
+// registerTranslationForGreaterThanMinCapacityTagName registers custom translation message
+// when "gt_min_capacity" validation is violated.
+func registerTranslationForGreaterThanMinCapacityTagName(ut ut.Translator) error {
+	return ut.Add(greaterThanMinCapacityTagName, "{0} must be greater than min_capacity", true)
+}
+
+// translationForGreaterThanMinCapacityTagName formats the message to be displayed
+// for "gt_min_capacity" struct tag validation.
+func translationForGreaterThanMinCapacityTagName(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T(greaterThanMinCapacityTagName, fe.Field())
+	return t
+}
+
+// registerTranslationForRequiredWithoutTagName registers custom translation message
+// when "required_without" validation is violated.
+func registerTranslationForRequiredWithoutTagName(ut ut.Translator) error {
+	return ut.Add(requiredWithoutTagName, "{0} is a required field", true)
+}
+
+// translationForRequiredWithoutTagName formats the message to be displayed
+// for "required_without" tag validation.
+func translationForRequiredWithoutTagName(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T(requiredWithoutTagName, fe.Field())
+	return t
+}
+
+// registerTranslationForExcludedWithTagName registers custom translation message
+// when "excluded_with" validation is violated.
+func registerTranslationForExcludedWithTagName(ut ut.Translator) error {
+	return ut.Add(excludedWithTagName, "{0} cannot be used together with {1}", true)
+}
+
+// translationForExcludedWithTagName formats the message to be displayed
+// for "excluded_with" tag validation.
+func translationForExcludedWithTagName(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T(excludedWithTagName, fe.Field(), strings.ToLower(fe.Param()))
+	return t
+}
+
+// registerTranslationForStrategyTagName registers custom translation message
+// when "strategy" validation is violated.
+func registerTranslationForStrategyTagName(ut ut.Translator) error {
+	return ut.Add(strategyTagName, "{0} must be one of: {1}", true)
+}
+
+// translationForStrategyTagName formats the message to be displayed
+// for "strategy" tag validation.
+func translationForStrategyTagName(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T(strategyTagName, fe.Field(), strings.Join(measurement.Strategies(), ", "))
+	return t
+}
+
+// validateStrategy checks that the field names a registered solving strategy.
+func validateStrategy(fl validator.FieldLevel) bool {
+	_, err := measurement.Lookup(fl.Field().String())
+	return err == nil
+}
+
+// registerTranslationForActionTagName registers custom translation message
+// when "action" validation is violated.
+func registerTranslationForActionTagName(ut ut.Translator) error {
+	return ut.Add(actionTagName, "{0} must be one of: {1}", true)
+}
+
+// translationForActionTagName formats the message to be displayed
+// for "action" tag validation.
+func translationForActionTagName(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T(actionTagName, fe.Field(), strings.Join(measurement.Actions(), ", "))
+	return t
+}
+
+// validateAction checks that the field names an action.
+func validateAction(fl validator.FieldLevel) bool {
+	return slices.Contains(measurement.Actions(), fl.Field().String())
+}
+
+// registerTranslationForHttpURLTagName registers custom translation message
+// when "http_url" validation is violated.
+func registerTranslationForHttpURLTagName(ut ut.Translator) error {
+	return ut.Add(httpURLTagName, "{0} must be a valid HTTP or HTTPS URL", true)
+}
+
+// translationForHttpURLTagName formats the message to be displayed
+// for "http_url" tag validation.
+func translationForHttpURLTagName(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T(httpURLTagName, fe.Field())
+	return t
+}
+
+// registerTranslationForRequiredWithTagName registers custom translation message
+// when "required_with" validation is violated.
+func registerTranslationForRequiredWithTagName(ut ut.Translator) error {
+	return ut.Add(requiredWithTagName, "{0} is a required field", true)
+}
+
+// translationForRequiredWithTagName formats the message to be displayed
+// for "required_with" tag validation.
+func translationForRequiredWithTagName(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T(requiredWithTagName, fe.Field())
+	return t
+}
+
+// registerTranslationForInventoryCapacityTagName registers custom translation message
+// when "inventory_capacity" validation is violated.
+func registerTranslationForInventoryCapacityTagName(ut ut.Translator) error {
+	return ut.Add(inventoryCapacityTagName, "{0} cannot have more than {1} as the number of jugs times the largest capacity", true)
+}
+
+// translationForInventoryCapacityTagName formats the message to be displayed
+// for "inventory_capacity" struct tag validation.
+func translationForInventoryCapacityTagName(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T(inventoryCapacityTagName, fe.Field(), strconv.Itoa(measurement.MaxInventoryCapacity))
+	return t
+}
+
 func init() {
 	// Instantiate a validator.
 	validate = validator.New()
@@ -72,8 +206,70 @@ func init() {
 		os.Exit(1)
 	}
 
+	// registers custom translation message when "required_without" error tag is reported
+	if err := validate.RegisterTranslation(requiredWithoutTagName, translator, registerTranslationForRequiredWithoutTagName, translationForRequiredWithoutTagName); err != nil {
+		fmt.Printf("error registering translations for %s tag: %v", requiredWithoutTagName, err)
+		os.Exit(1)
+	}
+
+	// registers custom translation message when "excluded_with" error tag is reported
+	if err := validate.RegisterTranslation(excludedWithTagName, translator, registerTranslationForExcludedWithTagName, translationForExcludedWithTagName); err != nil {
+		fmt.Printf("error registering translations for %s tag: %v", excludedWithTagName, err)
+		os.Exit(1)
+	}
+
+	// registers "strategy" validation and its custom translation message
+	if err := validate.RegisterValidation(strategyTagName, validateStrategy); err != nil {
+		fmt.Printf("error registering validation for %s tag: %v", strategyTagName, err)
+		os.Exit(1)
+	}
+	if err := validate.RegisterTranslation(strategyTagName, translator, registerTranslationForStrategyTagName, translationForStrategyTagName); err != nil {
+		fmt.Printf("error registering translations for %s tag: %v", strategyTagName, err)
+		os.Exit(1)
+	}
+
+	// registers "action" validation and its custom translation message
+	if err := validate.RegisterValidation(actionTagName, validateAction); err != nil {
+		fmt.Printf("error registering validation for %s tag: %v", actionTagName, err)
+		os.Exit(1)
+	}
+	if err := validate.RegisterTranslation(actionTagName, translator, registerTranslationForActionTagName, translationForActionTagName); err != nil {
+		fmt.Printf("error registering translations for %s tag: %v", actionTagName, err)
+		os.Exit(1)
+	}
+
+	// registers custom translation message when "http_url" error tag is reported
+	if err := validate.RegisterTranslation(httpURLTagName, translator, registerTranslationForHttpURLTagName, translationForHttpURLTagName); err != nil {
+		fmt.Printf("error registering translations for %s tag: %v", httpURLTagName, err)
+		os.Exit(1)
+	}
+
+	// registers custom translation message when "required_with" error tag is reported
+	if err := validate.RegisterTranslation(requiredWithTagName, translator, registerTranslationForRequiredWithTagName, translationForRequiredWithTagName); err != nil {
+		fmt.Printf("error registering translations for %s tag: %v", requiredWithTagName, err)
+		os.Exit(1)
+	}
+
+	// registers custom translation message when "gt_min_capacity" error tag is reported
+	if err := validate.RegisterTranslation(greaterThanMinCapacityTagName, translator, registerTranslationForGreaterThanMinCapacityTagName, translationForGreaterThanMinCapacityTagName); err != nil {
+		fmt.Printf("error registering translations for %s tag: %v", greaterThanMinCapacityTagName, err)
+		os.Exit(1)
+	}
+
+	// registers custom translation message when "inventory_capacity" error tag is reported
+	if err := validate.RegisterTranslation(inventoryCapacityTagName, translator, registerTranslationForInventoryCapacityTagName, translationForInventoryCapacityTagName); err != nil {
+		fmt.Printf("error registering translations for %s tag: %v", inventoryCapacityTagName, err)
+		os.Exit(1)
+	}
+
 	// registers validation for person.Person struct
 	validate.RegisterStructValidation(NewMeasurementStructLevelValidation, models.NewMeasurement{})
+
+	// registers validation for models.NewPuzzles struct
+	validate.RegisterStructValidation(NewPuzzlesStructLevelValidation, models.NewPuzzles{})
+
+	// registers validation for models.NewRecommendation struct
+	validate.RegisterStructValidation(NewRecommendationStructLevelValidation, models.NewRecommendation{})
 }
 
 // Check validates the provided model against it's declared tags.
@@ -100,7 +296,25 @@ func Check(val any) error {
 // NewMeasurementStructLevelValidation registers validation for models.NewMeasurement struct.
 func NewMeasurementStructLevelValidation(sl validator.StructLevel) {
 	req := sl.Current().Interface().(models.NewMeasurement)
-	if req.ZAmountWanted > req.XCap && req.ZAmountWanted > req.YCap {
+	// in accumulation mode, the receiving vessel may end up holding more than either jug.
+	if !req.Accumulate && req.ZAmountWanted > req.XCap && req.ZAmountWanted > req.YCap {
 		sl.ReportError(nil, "", "", lessThanXAndYCapacitiesStructTagName, "")
 	}
 }
+
+// NewPuzzlesStructLevelValidation registers validation for models.NewPuzzles struct.
+func NewPuzzlesStructLevelValidation(sl validator.StructLevel) {
+	req := sl.Current().Interface().(models.NewPuzzles)
+	if req.MaxCapacity <= req.MinCapacity {
+		sl.ReportError(req.MaxCapacity, "max_capacity", "MaxCapacity", greaterThanMinCapacityTagName, "")
+	}
+}
+
+// NewRecommendationStructLevelValidation registers validation for models.NewRecommendation struct.
+func NewRecommendationStructLevelValidation(sl validator.StructLevel) {
+	req := sl.Current().Interface().(models.NewRecommendation)
+	// every pair of jugs is searched, in time growing with their capacities.
+	if len(req.Inventory) > 0 && len(req.Inventory)*slices.Max(req.Inventory) > measurement.MaxInventoryCapacity {
+		sl.ReportError(req.Inventory, "inventory", "Inventory", inventoryCapacityTagName, "")
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package web
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ETagMatches tells whether the request's If-None-Match header matches
+// the given entity tag, in which case the representation the client
+// holds is still current. Entity tags are compared weakly, as If-None-Match
+// requires (RFC 9110, section 13.1.2).
+func ETagMatches(r *http.Request, etag string) bool {
+	header := strings.Join(r.Header.Values("If-None-Match"), ",")
+	if header == "" {
+		return false
+	}
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestETagMatches(t *testing.T) {
+	testCases := []struct {
+		name           string
+		ifNoneMatch    []string
+		expectedOutput bool
+	}{
+		{
+			name:           "no header",
+			expectedOutput: false,
+		},
+		{
+			name:           "same etag",
+			ifNoneMatch:    []string{`"abc"`},
+			expectedOutput: true,
+		},
+		{
+			name:           "different etag",
+			ifNoneMatch:    []string{`"abd"`},
+			expectedOutput: false,
+		},
+		{
+			name:           "list containing the etag",
+			ifNoneMatch:    []string{`"xyz" , "abc"`},
+			expectedOutput: true,
+		},
+		{
+			name:           "several headers",
+			ifNoneMatch:    []string{`"xyz"`, `"abc"`},
+			expectedOutput: true,
+		},
+		{
+			name:           "weak etag",
+			ifNoneMatch:    []string{`W/"abc"`},
+			expectedOutput: true,
+		},
+		{
+			name:           "any etag",
+			ifNoneMatch:    []string{`*`},
+			expectedOutput: true,
+		},
+		{
+			name:           "unquoted etag",
+			ifNoneMatch:    []string{`abc`},
+			expectedOutput: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/measure?x=3&y=5&z=4", nil)
+			for _, value := range tc.ifNoneMatch {
+				req.Header.Add("If-None-Match", value)
+			}
+			require.Equal(t, tc.expectedOutput, ETagMatches(req, `"abc"`))
+		})
+	}
+}
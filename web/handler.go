@@ -0,0 +1,159 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package web
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/tiagomelo/golang-waterjug-api/cache"
+	"github.com/tiagomelo/golang-waterjug-api/measurement"
+	"github.com/tiagomelo/golang-waterjug-api/render"
+	"github.com/tiagomelo/golang-waterjug-api/validate"
+)
+
+// Handler is an HTTP handler that returns the error it fails with instead
+// of responding to it. Adapt turns it into an http.Handler.
+type Handler func(w http.ResponseWriter, r *http.Request) error
+
+// RequestError is returned when a request is malformed, such as when its
+// body can't be decoded or a parameter can't be parsed.
+type RequestError struct {
+	Err error
+}
+
+// Error implements the error interface.
+func (e *RequestError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// BadRequest marks err as caused by a malformed request.
+func BadRequest(err error) error {
+	return &RequestError{Err: err}
+}
+
+// StatusError is returned when a request fails with a status code of its
+// own, such as when what it refers to doesn't exist.
+type StatusError struct {
+	Code int
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *StatusError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// WithStatus marks err as failing the request with the given status code.
+func WithStatus(code int, err error) error {
+	return &StatusError{Code: code, Err: err}
+}
+
+// badSolverRequests are the solver errors caused by what was asked for.
+var badSolverRequests = []error{
+	measurement.ErrEmptyGoal,
+	measurement.ErrUnsupportedGoal,
+	measurement.ErrUnsupportedActionOrder,
+	measurement.ErrGraphTooLarge,
+	measurement.ErrUnknownAction,
+	measurement.ErrIllegalAction,
+}
+
+// MapError maps err into the problem to respond with:
+//
+//   - validation errors and malformed requests are bad requests;
+//   - errors with a status code of their own are responded to with it;
+//   - measurements without solution are unprocessable, with the reason as extensions;
+//   - solutions too long to be rendered as images are unprocessable too;
+//   - solver errors caused by what was asked for are bad requests;
+//   - cache errors make the service unavailable;
+//   - anything else is an internal error, whose detail is not disclosed.
+func MapError(r *http.Request, err error) *Problem {
+	return MapErrorAt(r.URL.RequestURI(), err)
+}
+
+// MapErrorAt maps err like MapError, for errors that don't come from
+// handling a request, such as those of asynchronous jobs, identifying
+// their occurrence by instance.
+func MapErrorAt(instance string, err error) *Problem {
+	var requestErr *RequestError
+	var statusErr *StatusError
+	var noSolution *measurement.NoSolutionError
+	var cacheErr *cache.Error
+	switch {
+	case validate.IsFieldErrors(err), errors.As(err, &requestErr):
+		return newProblem(instance, http.StatusBadRequest, err)
+	case errors.As(err, &statusErr):
+		return newProblem(instance, statusErr.Code, statusErr.Err)
+	case errors.As(err, &noSolution):
+		problem := newProblem(instance, http.StatusUnprocessableEntity, err)
+		problem.Title = "No Solution"
+		problem.Extensions = noSolution.Model()
+		return problem
+	case errors.Is(err, render.ErrTooManySteps):
+		problem := newProblem(instance, http.StatusUnprocessableEntity, err)
+		problem.Type = ProblemTypeTooManySteps
+		problem.Title = "Too Many Steps"
+		return problem
+	case errors.As(err, &cacheErr):
+		return newProblem(instance, http.StatusServiceUnavailable, errors.New("cache is unavailable"))
+	}
+	for _, target := range badSolverRequests {
+		if errors.Is(err, target) {
+			return newProblem(instance, http.StatusBadRequest, err)
+		}
+	}
+	return newProblem(instance, http.StatusInternalServerError, nil)
+}
+
+// loggerKey is the context key of the logger given to Adapt.
+type loggerKey struct{}
+
+// ProblemFor maps err into the problem to respond with, like MapError, and
+// logs it along with the request, using the logger given to Adapt.
+func ProblemFor(r *http.Request, err error) *Problem {
+	problem := MapError(r, err)
+	log, ok := r.Context().Value(loggerKey{}).(*slog.Logger)
+	if !ok {
+		log = slog.Default()
+	}
+	level := slog.LevelInfo
+	if problem.Status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	log.LogAttrs(r.Context(), level, "request failed",
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.String("remoteaddr", r.RemoteAddr),
+		slog.Int("status", problem.Status),
+		slog.String("error", err.Error()),
+	)
+	return problem
+}
+
+// Adapt returns an http.Handler calling h. When h fails, the error is
+// logged along with the request and responded to with the problem it
+// maps to. Handlers reporting errors by themselves, such as the errors
+// of single items of a batch, get the same treatment with ProblemFor.
+func Adapt(log *slog.Logger, h Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(context.WithValue(r.Context(), loggerKey{}, log))
+		if err := h(w, r); err != nil {
+			RespondWithProblem(w, ProblemFor(r, err))
+		}
+	})
+}
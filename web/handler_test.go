@@ -0,0 +1,182 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/cache"
+	"github.com/tiagomelo/golang-waterjug-api/measurement"
+	"github.com/tiagomelo/golang-waterjug-api/render"
+	"github.com/tiagomelo/golang-waterjug-api/validate"
+)
+
+func TestMapError(t *testing.T) {
+	testCases := []struct {
+		name           string
+		err            error
+		expectedType   string
+		expectedStatus int
+		expectedDetail string
+	}{
+		{
+			name:           "malformed request",
+			err:            BadRequest(errors.New("x must be an integer")),
+			expectedType:   ProblemTypeBadRequest,
+			expectedStatus: http.StatusBadRequest,
+			expectedDetail: "x must be an integer",
+		},
+		{
+			name:           "decode error",
+			err:            BadRequest(json.Unmarshal([]byte(`{`), &struct{}{})),
+			expectedType:   ProblemTypeBadRequest,
+			expectedStatus: http.StatusBadRequest,
+			expectedDetail: "unexpected end of JSON input",
+		},
+		{
+			name:           "validation error",
+			err:            validate.FieldErrors{{Field: "x_capacity", Error: "x_capacity is a required field"}},
+			expectedType:   ProblemTypeValidation,
+			expectedStatus: http.StatusBadRequest,
+			expectedDetail: "the request has invalid fields",
+		},
+		{
+			name:           "status error",
+			err:            WithStatus(http.StatusNotFound, errors.New("job 42 not found")),
+			expectedType:   ProblemTypeNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedDetail: "job 42 not found",
+		},
+		{
+			name:           "no solution",
+			err:            &measurement.NoSolutionError{Reason: measurement.ReasonTargetExceedsCapacity, XCap: 3, YCap: 5, Target: 8},
+			expectedType:   ProblemTypeNoSolution,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedDetail: "no solution: 8 exceeds both capacities, 3 and 5",
+		},
+		{
+			name:           "too many steps to render",
+			err:            fmt.Errorf("%w: the solution has 201 steps, more than the limit of 200", render.ErrTooManySteps),
+			expectedType:   ProblemTypeTooManySteps,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedDetail: "too many steps to render: the solution has 201 steps, more than the limit of 200",
+		},
+		{
+			name:           "solver error",
+			err:            fmt.Errorf("%w: more than 2000 reachable states", measurement.ErrGraphTooLarge),
+			expectedType:   ProblemTypeBadRequest,
+			expectedStatus: http.StatusBadRequest,
+			expectedDetail: "state graph too large: more than 2000 reachable states",
+		},
+		{
+			name:           "cache error",
+			err:            fmt.Errorf("failed to retrieve solution: %w", &cache.Error{Err: errors.New("connection refused")}),
+			expectedType:   ProblemTypeUnavailable,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedDetail: "cache is unavailable",
+		},
+		{
+			name:           "cache error wrapping EOF",
+			err:            fmt.Errorf("failed to store solution: %w", &cache.Error{Err: io.EOF}),
+			expectedType:   ProblemTypeUnavailable,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedDetail: "cache is unavailable",
+		},
+		{
+			name:           "unknown error",
+			err:            errors.New("render error"),
+			expectedType:   ProblemTypeInternal,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/measure", nil)
+			problem := MapError(req, tc.err)
+			require.Equal(t, tc.expectedType, problem.Type)
+			require.Equal(t, tc.expectedStatus, problem.Status)
+			require.Equal(t, tc.expectedDetail, problem.Detail)
+			require.Equal(t, "/v1/measure", problem.Instance)
+		})
+	}
+}
+
+func TestMapErrorAt(t *testing.T) {
+	problem := MapErrorAt("/v1/jobs/42", WithStatus(http.StatusConflict, errors.New("job 42 has already finished")))
+	require.Equal(t, &Problem{
+		Type:     "about:blank",
+		Title:    "Conflict",
+		Status:   http.StatusConflict,
+		Detail:   "job 42 has already finished",
+		Instance: "/v1/jobs/42",
+	}, problem)
+}
+
+func TestAdapt(t *testing.T) {
+	testCases := []struct {
+		name               string
+		handler            Handler
+		expectedOutput     string
+		expectedStatusCode int
+		expectedLog        string
+	}{
+		{
+			name: "happy path",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				RespondWithJson(w, http.StatusOK, map[string]int{"steps": 4})
+				return nil
+			},
+			expectedOutput:     `{"steps":4}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: "client error",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return BadRequest(errors.New(`unsupported format "gif"`))
+			},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request","title":"Bad Request","status":400,"detail":"unsupported format \"gif\"","instance":"/v1/measure"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedLog:        `level=INFO msg="request failed" method=POST path=/v1/measure remoteaddr=192.0.2.1:1234 status=400 error="unsupported format \"gif\""`,
+		},
+		{
+			name: "server error",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return errors.New("render error")
+			},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#internal-error","title":"Internal Server Error","status":500,"instance":"/v1/measure"}`,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedLog:        `level=ERROR msg="request failed" method=POST path=/v1/measure remoteaddr=192.0.2.1:1234 status=500 error="render error"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			log := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey {
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))
+			req := httptest.NewRequest(http.MethodPost, "/v1/measure", nil)
+			recorder := httptest.NewRecorder()
+			Adapt(log, tc.handler).ServeHTTP(recorder, req)
+			require.Equal(t, tc.expectedStatusCode, recorder.Code)
+			require.Equal(t, tc.expectedOutput, recorder.Body.String())
+			require.Equal(t, tc.expectedLog, strings.TrimSuffix(logs.String(), "\n"))
+		})
+	}
+}
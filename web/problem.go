@@ -0,0 +1,140 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/tiagomelo/golang-waterjug-api/validate"
+)
+
+// ProblemContentType is the media type of problem details (RFC 7807).
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypeBaseURI is the base of the URIs identifying problem types.
+// Each type is documented under its own heading in doc/problems.md.
+const ProblemTypeBaseURI = "https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#"
+
+// Problem types.
+const (
+	ProblemTypeBadRequest       = ProblemTypeBaseURI + "bad-request"
+	ProblemTypeValidation       = ProblemTypeBaseURI + "validation-error"
+	ProblemTypeNotFound         = ProblemTypeBaseURI + "not-found"
+	ProblemTypeMethodNotAllowed = ProblemTypeBaseURI + "method-not-allowed"
+	ProblemTypeNoSolution       = ProblemTypeBaseURI + "no-solution"
+	ProblemTypeTooManySteps     = ProblemTypeBaseURI + "too-many-steps"
+	ProblemTypeInternal         = ProblemTypeBaseURI + "internal-error"
+	ProblemTypeUnavailable      = ProblemTypeBaseURI + "service-unavailable"
+)
+
+// problemTypes maps status codes to the problem type used for them
+// when no more specific type applies. Other status codes use about:blank,
+// which means the problem has no semantics beyond the status code.
+var problemTypes = map[int]string{
+	http.StatusBadRequest:          ProblemTypeBadRequest,
+	http.StatusNotFound:            ProblemTypeNotFound,
+	http.StatusMethodNotAllowed:    ProblemTypeMethodNotAllowed,
+	http.StatusUnprocessableEntity: ProblemTypeNoSolution,
+	http.StatusInternalServerError: ProblemTypeInternal,
+	http.StatusServiceUnavailable:  ProblemTypeUnavailable,
+}
+
+// Problem is a problem details object as defined by RFC 7807.
+type Problem struct {
+	Type     string                `json:"type"`               // URI identifying the problem type
+	Title    string                `json:"title"`              // short summary of the problem type
+	Status   int                   `json:"status"`             // HTTP status code
+	Detail   string                `json:"detail,omitempty"`   // explanation of this occurrence
+	Instance string                `json:"instance,omitempty"` // URI of this occurrence
+	Errors   []validate.FieldError `json:"errors,omitempty"`   // invalid request fields
+	// Extensions holds additional members, which are serialized after
+	// the standard ones. It must marshal to a JSON object whose members
+	// don't clash with them.
+	Extensions any `json:"-"`
+}
+
+// NewProblem creates the problem describing err for the given request.
+// Validation errors get their own type and list the invalid fields.
+func NewProblem(r *http.Request, code int, err error) *Problem {
+	return newProblem(r.URL.RequestURI(), code, err)
+}
+
+// newProblem creates the problem describing err, identifying its
+// occurrence by instance.
+func newProblem(instance string, code int, err error) *Problem {
+	problem := &Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(code),
+		Status:   code,
+		Instance: instance,
+	}
+	if problemType, ok := problemTypes[code]; ok {
+		problem.Type = problemType
+	}
+	if fieldErrors := validate.GetFieldErrors(err); fieldErrors != nil {
+		problem.Type = ProblemTypeValidation
+		problem.Title = "Validation Failed"
+		problem.Detail = "the request has invalid fields"
+		problem.Errors = fieldErrors
+		return problem
+	}
+	if err != nil {
+		problem.Detail = err.Error()
+	}
+	return problem
+}
+
+// MarshalJSON implements the json.Marshaler interface, appending the
+// extension members to the standard ones.
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	type problem Problem
+	standard, err := marshal((*problem)(p))
+	if err != nil || p.Extensions == nil {
+		return standard, err
+	}
+	extensions, err := marshal(p.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	if len(extensions) < 2 || extensions[0] != '{' {
+		return nil, errors.New("problem extensions must be a JSON object")
+	}
+	if string(extensions) == "{}" {
+		return standard, nil
+	}
+	merged := append(standard[:len(standard)-1], ',')
+	return append(merged, extensions[1:]...), nil
+}
+
+// marshal returns the JSON encoding of v. Unlike json.Marshal, it leaves
+// characters such as & alone, which are common in instance URIs.
+func marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// RespondWithProblem responds with the given problem as application/problem+json.
+func RespondWithProblem(w http.ResponseWriter, problem *Problem) {
+	response, _ := marshal(problem)
+	Respond(w, problem.Status, ProblemContentType, response)
+}
+
+// NotFound is an HTTP handler responding with a not found problem.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	RespondWithError(w, r, http.StatusNotFound, nil)
+}
+
+// MethodNotAllowed is an HTTP handler responding with a method not allowed problem.
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	RespondWithError(w, r, http.StatusMethodNotAllowed, nil)
+}
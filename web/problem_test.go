@@ -0,0 +1,126 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/validate"
+)
+
+func TestRespondWithError(t *testing.T) {
+	testCases := []struct {
+		name               string
+		code               int
+		err                error
+		expectedOutput     string
+		expectedStatusCode int
+	}{
+		{
+			name:               "bad request",
+			code:               http.StatusBadRequest,
+			err:                errors.New("x must be an integer"),
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#bad-request","title":"Bad Request","status":400,"detail":"x must be an integer","instance":"/v1/measure?format=svg"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "validation error",
+			code: http.StatusBadRequest,
+			err: validate.FieldErrors{
+				{Field: "x_capacity", Error: "x_capacity is a required field"},
+				{Field: "format", Error: "format must be one of [json csv]"},
+			},
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#validation-error","title":"Validation Failed","status":400,"detail":"the request has invalid fields","instance":"/v1/measure?format=svg","errors":[{"field":"x_capacity","error":"x_capacity is a required field"},{"field":"format","error":"format must be one of [json csv]"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "internal error",
+			code:               http.StatusInternalServerError,
+			err:                errors.New("cache error"),
+			expectedOutput:     `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#internal-error","title":"Internal Server Error","status":500,"detail":"cache error","instance":"/v1/measure?format=svg"}`,
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name:               "status without a problem type",
+			code:               http.StatusConflict,
+			err:                errors.New("conflict"),
+			expectedOutput:     `{"type":"about:blank","title":"Conflict","status":409,"detail":"conflict","instance":"/v1/measure?format=svg"}`,
+			expectedStatusCode: http.StatusConflict,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/measure?format=svg", nil)
+			recorder := httptest.NewRecorder()
+			RespondWithError(recorder, req, tc.code, tc.err)
+			require.Equal(t, tc.expectedStatusCode, recorder.Code)
+			require.Equal(t, ProblemContentType, recorder.Header().Get("Content-Type"))
+			require.Equal(t, tc.expectedOutput, recorder.Body.String())
+		})
+	}
+}
+
+func TestProblemExtensions(t *testing.T) {
+	testCases := []struct {
+		name           string
+		extensions     any
+		expectedOutput string
+		expectedError  error
+	}{
+		{
+			name: "struct",
+			extensions: struct {
+				Reason string `json:"reason"`
+				GCD    int    `json:"gcd"`
+			}{Reason: "gcd_mismatch", GCD: 2},
+			expectedOutput: `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#no-solution","title":"No Solution","status":422,"reason":"gcd_mismatch","gcd":2}`,
+		},
+		{
+			name:           "empty object",
+			extensions:     map[string]int{},
+			expectedOutput: `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#no-solution","title":"No Solution","status":422}`,
+		},
+		{
+			name:          "not an object",
+			extensions:    []int{1, 2},
+			expectedError: errors.New("json: error calling MarshalJSON for type *web.Problem: problem extensions must be a JSON object"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			problem := &Problem{
+				Type:       ProblemTypeNoSolution,
+				Title:      "No Solution",
+				Status:     http.StatusUnprocessableEntity,
+				Extensions: tc.extensions,
+			}
+			output, err := json.Marshal(problem)
+			if err != nil {
+				if tc.expectedError == nil {
+					t.Fatalf(`expected no error, got "%v"`, err)
+				}
+				require.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				if tc.expectedError != nil {
+					t.Fatalf(`expected error "%v", got nil`, tc.expectedError)
+				}
+				require.Equal(t, tc.expectedOutput, string(output))
+			}
+		})
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/v2/measure", nil)
+	recorder := httptest.NewRecorder()
+	NotFound(recorder, req)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+	require.Equal(t, `{"type":"https://github.com/tiagomelo/golang-waterjug-api/blob/main/doc/problems.md#not-found","title":"Not Found","status":404,"instance":"/v2/measure"}`, recorder.Body.String())
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package web
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+// IntParam parses the named query parameter as an integer, 0 when absent.
+func IntParam(r *http.Request, name string) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.New(name + " must be an integer")
+	}
+	return v, nil
+}
+
+// ParseMeasurementQuery extracts the capacities and the desired amount
+// from the "x", "y" and "z" query parameters. Absent ones are left to
+// validation.
+func ParseMeasurementQuery(r *http.Request) (*models.NewMeasurement, error) {
+	xCap, err := IntParam(r, "x")
+	if err != nil {
+		return nil, err
+	}
+	yCap, err := IntParam(r, "y")
+	if err != nil {
+		return nil, err
+	}
+	zAmountWanted, err := IntParam(r, "z")
+	if err != nil {
+		return nil, err
+	}
+	return &models.NewMeasurement{
+		XCap:          xCap,
+		YCap:          yCap,
+		ZAmountWanted: zAmountWanted,
+	}, nil
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2024 Tiago Melo. All rights reserved.
+// Use of this source code is governed by the MIT License that can be found in
+// the LICENSE file.
+
+package web
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagomelo/golang-waterjug-api/measurement/models"
+)
+
+func TestParseMeasurementQuery(t *testing.T) {
+	testCases := []struct {
+		name           string
+		query          string
+		expectedOutput *models.NewMeasurement
+		expectedError  string
+	}{
+		{
+			name:           "happy path",
+			query:          "x=3&y=5&z=4",
+			expectedOutput: &models.NewMeasurement{XCap: 3, YCap: 5, ZAmountWanted: 4},
+		},
+		{
+			name:           "absent parameters",
+			query:          "y=5",
+			expectedOutput: &models.NewMeasurement{YCap: 5},
+		},
+		{
+			name:          "x is not an integer",
+			query:         "x=a&y=5&z=4",
+			expectedError: "x must be an integer",
+		},
+		{
+			name:          "y is not an integer",
+			query:         "x=3&y=5.5&z=4",
+			expectedError: "y must be an integer",
+		},
+		{
+			name:          "z is not an integer",
+			query:         "x=3&y=5&z=four",
+			expectedError: "z must be an integer",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/v1/measure?"+tc.query, nil)
+			require.NoError(t, err)
+			output, err := ParseMeasurementQuery(req)
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedOutput, output)
+		})
+	}
+}
@@ -9,9 +9,9 @@ import (
 	"net/http"
 )
 
-// RespondWithError responds a json with an error message
-func RespondWithError(w http.ResponseWriter, code int, message string) {
-	RespondWithJson(w, code, map[string]string{"error": message})
+// RespondWithError responds with a problem describing err.
+func RespondWithError(w http.ResponseWriter, r *http.Request, code int, err error) {
+	RespondWithProblem(w, NewProblem(r, code, err))
 }
 
 // RespondWithJson responds a json with an error message
@@ -23,6 +23,13 @@ func RespondWithJson(w http.ResponseWriter, code int, payload interface{}) {
 	w.Write(response)
 }
 
+// Respond responds with the given content type and body
+func Respond(w http.ResponseWriter, code int, contentType string, body []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(code)
+	w.Write(body)
+}
+
 // RespondWithStatus responds with an HTTP status code
 func RespondWithStatus(w http.ResponseWriter, code int) {
 	w.WriteHeader(code)